@@ -3,20 +3,30 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/dappnode/validator-tracker/internal/adapters/api"
 	"github.com/dappnode/validator-tracker/internal/adapters/beacon"
 	"github.com/dappnode/validator-tracker/internal/adapters/brain"
 	"github.com/dappnode/validator-tracker/internal/adapters/dappmanager"
+	"github.com/dappnode/validator-tracker/internal/adapters/execution"
+	"github.com/dappnode/validator-tracker/internal/adapters/keysource"
 	"github.com/dappnode/validator-tracker/internal/adapters/notifier"
+	"github.com/dappnode/validator-tracker/internal/adapters/relay"
+	"github.com/dappnode/validator-tracker/internal/adapters/store"
+	"github.com/dappnode/validator-tracker/internal/adapters/web3signer"
 	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
 	"github.com/dappnode/validator-tracker/internal/application/services"
 	"github.com/dappnode/validator-tracker/internal/config"
 	"github.com/dappnode/validator-tracker/internal/logger"
+	"github.com/dappnode/validator-tracker/internal/metrics"
 )
 
 func main() {
@@ -35,6 +45,11 @@ func main() {
 	// Initialize network-specific notification correlation IDs
 	domain.InitNotifications(cfg.Network)
 
+	// Prepare context and WaitGroup for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+
 	// Initialize adapters
 	dappmanager := dappmanager.NewDappManagerAdapter(cfg.DappmanagerUrl, cfg.SignerDnpName)
 	notifier := notifier.NewNotifier(
@@ -42,37 +57,149 @@ func main() {
 		cfg.BeaconchaUrl,
 		cfg.BrainUrl,
 		cfg.Network,
+		cfg.NotificationCategory,
 		cfg.SignerDnpName,
+		cfg.NotifierTimeout,
 	)
-	brain := brain.NewBrainAdapter(cfg.BrainUrl)
-	beacon, err := beacon.NewBeaconAdapter(cfg.BeaconEndpoint)
-	// TODO: do not err on initialization, allow connection errors later. See https://github.com/attestantio/go-eth2-client/issues/254
+	keySource := newKeySource(cfg)
+	// Connects in the background, the checker reports the beacon node as unavailable meanwhile
+	beacon := beacon.NewBeaconAdapters(ctx, cfg.BeaconEndpoints, cfg.BeaconCrossCheck, cfg.BeaconTimeout)
+
+	proposalChecker := &services.ProposalChecker{
+		Beacon:              beacon,
+		Execution:           execution.NewExecutionAdapter(cfg.ExecutionEndpoint, cfg.ExecutionTimeout),
+		FeeRecipients:       cfg.FeeRecipients,
+		DefaultFeeRecipient: cfg.DefaultFeeRecipient,
+	}
+	if len(cfg.MevRelays) > 0 {
+		proposalChecker.Relays = relay.NewRelayAdapter(cfg.MevRelays, cfg.RelayTimeout)
+	}
+
+	// A broken state file should not stop the tracker, it just loses the history
+	stateStore, err := store.NewFileStore(cfg.StateFile)
 	if err != nil {
-		logger.Fatal("Failed to initialize beacon adapter. A live connection is required on startup: %v", err)
+		logger.Error("Failed to open state file, state will only be kept in memory: %v", err)
 	}
 
-	// Prepare context and WaitGroup for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	var wg sync.WaitGroup
+	silencer := &services.Silencer{
+		Notifier:      notifier,
+		Dappmanager:   dappmanager,
+		Store:         stateStore,
+		CheckInterval: cfg.SilenceCheckInterval,
+	}
+	if err := silencer.LoadSilences(); err != nil {
+		logger.Error("Failed to load persisted maintenance windows: %v", err)
+	}
+	if cfg.Silence != nil {
+		if _, err := silencer.Add(*cfg.Silence); err != nil {
+			logger.Warn("Ignoring the configured maintenance window: %v", err)
+		}
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		silencer.Run(ctx)
+	}()
 
 	// Start the duties checker service in a goroutine
+	tagRouter := &services.TagRouter{
+		Notifier: notifier,
+		Tags:     tagSettings(cfg),
+	}
+
+	indexResolver := &services.IndexResolver{
+		Beacon:                beacon,
+		Store:                 stateStore,
+		StatusRefreshInterval: cfg.StatusRefreshInterval,
+	}
+	if err := indexResolver.LoadIndices(); err != nil {
+		logger.Error("Failed to load persisted validator indices: %v", err)
+	}
+
 	dutiesChecker := &services.DutiesChecker{
+		Beacon:          beacon,
+		Keys:            keySource,
+		Notifier:        notifier,
+		Dappmanager:     dappmanager,
+		Tags:            tagRouter,
+		Resolver:        indexResolver,
+		Network:         expectedConfigName(cfg),
+		CheckerSettings: checkerSettings(cfg),
+		Attestations:    &services.AttestationChecker{Beacon: beacon},
+		SyncCommittee:   &services.SyncCommitteeChecker{Beacon: beacon},
+		Rewards:         &services.RewardsChecker{Beacon: beacon},
+		Proposals:       proposalChecker,
+		SlashedNotified: make(map[domain.ValidatorIndex]bool),
+		LivenessStates:  make(map[domain.ValidatorIndex]*domain.LivenessState),
+		Store:           stateStore,
+		Silencer:        silencer,
+	}
+	if err := dutiesChecker.LoadState(); err != nil {
+		logger.Error("Failed to load persisted checker state: %v", err)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		dutiesChecker.Run(ctx)
+	}()
+
+	dutyScheduler := &services.DutyScheduler{
 		Beacon:            beacon,
-		Brain:             brain,
-		Notifier:          notifier,
+		Tags:              tagRouter,
 		Dappmanager:       dappmanager,
-		PollInterval:      1 * time.Minute,
-		SlashedNotified:   make(map[domain.ValidatorIndex]bool),
-		PreviouslyAllLive: true, // assume all validators were live at start
-		PreviouslyOffline: false,
+		Checker:           dutiesChecker,
+		Silencer:          silencer,
+		SchedulerSettings: schedulerSettings(cfg),
 	}
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		dutiesChecker.Run(ctx)
+		dutyScheduler.Run(ctx)
 	}()
 
+	healthChecker := &services.HealthChecker{
+		Beacon:      beacon,
+		Keys:        keySource,
+		Notifier:    notifier,
+		Dappmanager: dappmanager,
+		Checker:     dutiesChecker,
+		MaxCheckAge: cfg.MaxCheckAge,
+		StartedAt:   time.Now(),
+	}
+
+	// Start the metrics and API servers in goroutines
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		runHttpServer(ctx, "metrics", cfg.MetricsAddress, metricsMux)
+	}()
+	go func() {
+		defer wg.Done()
+		runHttpServer(ctx, "API", cfg.ApiAddress, api.NewHandler(dutiesChecker, healthChecker, dutyScheduler, silencer, cfg.HealthCheckTimeout))
+	}()
+
+	// Reload the settings that can change at runtime on SIGHUP
+	handleReload(ctx, func() {
+		reloaded, err := config.Load()
+		if err != nil {
+			logger.Error("Config not reloaded, the previous settings are kept: %v", err)
+			return
+		}
+		for _, setting := range config.RestartRequired(cfg, reloaded) {
+			logger.Warn("Setting %s changed, restart the tracker to apply it", setting)
+		}
+		dutiesChecker.Reload(checkerSettings(reloaded))
+		dutyScheduler.Reload(schedulerSettings(reloaded))
+		if reloaded.Silence != nil {
+			if _, err := silencer.Add(*reloaded.Silence); err != nil {
+				logger.Warn("Ignoring the configured maintenance window: %v", err)
+			}
+		}
+		logger.Info("Config reloaded from %s and environment variables", reloaded.ConfigFile)
+	})
+
 	// Handle graceful shutdown
 	handleShutdown(cancel)
 
@@ -81,6 +208,127 @@ func main() {
 	logger.Info("All services stopped. Shutting down.")
 }
 
+// runHttpServer serves the handler until the context is cancelled
+func runHttpServer(ctx context.Context, name string, address string, handler http.Handler) {
+	server := &http.Server{Addr: address, Handler: handler}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Error shutting down %s server: %v", name, err)
+		}
+	}()
+
+	logger.Info("Serving %s on %s", name, address)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("The %s server stopped: %v", name, err)
+	}
+}
+
+// newKeySource merges the validators of the configured signer, falling back to the other one if enabled,
+// with the ones listed in the config and in the validators file
+func newKeySource(cfg config.Config) ports.KeySource {
+	var sources []keysource.Source
+	if cfg.KeySource != config.KeySourceNone {
+		brainSource := keysource.Source{Name: config.KeySourceBrain, Adapter: brain.NewBrainAdapter(cfg.BrainUrl, cfg.BrainTimeout)}
+		web3signerSource := keysource.Source{Name: config.KeySourceWeb3Signer, Adapter: web3signer.NewWeb3SignerAdapter(cfg.Web3SignerEndpoint, cfg.Web3SignerTimeout)}
+		signers := []keysource.Source{brainSource, web3signerSource}
+		if cfg.KeySource == config.KeySourceWeb3Signer {
+			signers = []keysource.Source{web3signerSource, brainSource}
+		}
+		if !cfg.KeySourceFallback {
+			signers = signers[:1]
+		}
+		sources = append(sources, keysource.Source{Name: cfg.KeySource, Adapter: keysource.NewFallbackKeySource(signers...)})
+	}
+	if !cfg.StaticValidators.Empty() {
+		sources = append(sources, keysource.Source{Name: "config", Adapter: keysource.NewStaticKeySource(cfg.StaticValidators)})
+	}
+	if cfg.ValidatorsFile != "" {
+		sources = append(sources, keysource.Source{Name: cfg.ValidatorsFile, Adapter: keysource.NewFileKeySource(cfg.ValidatorsFile)})
+	}
+	return keysource.NewCompositeKeySource(sources...)
+}
+
+// tagSettings returns the notification settings of each configured tag. A tag with its own notifier URL or
+// category gets its own notifier, the others use the default one.
+func tagSettings(cfg config.Config) map[string]services.TagSettings {
+	settings := make(map[string]services.TagSettings, len(cfg.Tags))
+	for tag, tagCfg := range cfg.Tags {
+		tagSettings := services.TagSettings{
+			Disabled:              tagCfg.Disabled,
+			DisabledNotifications: tagCfg.DisabledNotifications,
+		}
+		if tagCfg.NotifierUrl != "" || tagCfg.Category != "" {
+			notifierUrl, category := cfg.NotifierUrl, cfg.NotificationCategory
+			if tagCfg.NotifierUrl != "" {
+				notifierUrl = tagCfg.NotifierUrl
+			}
+			if tagCfg.Category != "" {
+				category = tagCfg.Category
+			}
+			tagSettings.Notifier = notifier.NewNotifier(notifierUrl, cfg.BeaconchaUrl, cfg.BrainUrl, cfg.Network, category, cfg.SignerDnpName, cfg.NotifierTimeout)
+		}
+		settings[tag] = tagSettings
+	}
+	return settings
+}
+
+// expectedConfigName returns the CONFIG_NAME the beacon node should report. Custom networks are not checked,
+// as their name is only a label chosen in the config.
+func expectedConfigName(cfg config.Config) string {
+	if cfg.CustomNetwork {
+		return ""
+	}
+	return cfg.Network
+}
+
+// checkerSettings returns the settings of the duties checker, which are reloaded on SIGHUP
+func checkerSettings(cfg config.Config) services.CheckerSettings {
+	return services.CheckerSettings{
+		PollInterval:               cfg.PollInterval,
+		SyncParticipationThreshold: cfg.SyncParticipationThreshold,
+		MaxLookbackEpochs:          cfg.MaxLookbackEpochs,
+		BeaconOutageAlertAfter:     cfg.BeaconOutageAlertAfter,
+		MinBeaconPeers:             cfg.MinBeaconPeers,
+		MissedEpochsToAlert:        cfg.MissedEpochsToAlert,
+		LiveEpochsToResolve:        cfg.LiveEpochsToResolve,
+		DisableAttestations:        !cfg.CheckAttestations,
+		DisableSyncCommittee:       !cfg.CheckSyncCommittee,
+		DisableRewards:             !cfg.CheckRewards,
+		DisableProposalDetails:     !cfg.CheckProposalDetails,
+	}
+}
+
+// schedulerSettings returns the settings of the duty scheduler, which are reloaded on SIGHUP
+func schedulerSettings(cfg config.Config) services.SchedulerSettings {
+	return services.SchedulerSettings{
+		RefreshInterval:      cfg.DutyForecastInterval,
+		ProposalNoticeBefore: cfg.ProposalNoticeBefore,
+	}
+}
+
+// handleReload calls reload on every SIGHUP until the context is done
+func handleReload(ctx context.Context, reload func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigChan)
+		for {
+			select {
+			case <-sigChan:
+				logger.Info("Received SIGHUP, reloading config...")
+				reload()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
 // handleShutdown listens for SIGINT/SIGTERM and cancels the context
 func handleShutdown(cancel context.CancelFunc) {
 	sigChan := make(chan os.Signal, 1)
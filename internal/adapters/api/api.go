@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// StatusSource is implemented by the duties checker
+type StatusSource interface {
+	Status() domain.TrackerStatus
+	EpochResult(epoch domain.Epoch) (domain.EpochResult, bool, error)
+}
+
+// HealthSource is implemented by the health checker
+type HealthSource interface {
+	Live() domain.LivenessReport
+	Check(ctx context.Context) domain.HealthReport
+}
+
+// ForecastSource is implemented by the duty scheduler
+type ForecastSource interface {
+	Forecast() domain.DutyForecast
+}
+
+// SilenceSource is implemented by the silencer
+type SilenceSource interface {
+	List() []domain.Silence
+	Add(silence domain.Silence) (domain.Silence, error)
+	Remove(ctx context.Context, id string) bool
+}
+
+// Handler serves the JSON API used by the dappnode UI and scripts to query the tracker.
+// It is read-only except for the maintenance windows.
+type Handler struct {
+	source   StatusSource
+	health   HealthSource
+	forecast ForecastSource
+	silences SilenceSource
+	mux      *http.ServeMux
+	// Bounds the time spent querying the dependencies on each readiness request
+	healthCheckTimeout time.Duration
+}
+
+func NewHandler(source StatusSource, health HealthSource, forecast ForecastSource, silences SilenceSource, healthCheckTimeout time.Duration) *Handler {
+	h := &Handler{
+		source:             source,
+		health:             health,
+		forecast:           forecast,
+		silences:           silences,
+		mux:                http.NewServeMux(),
+		healthCheckTimeout: healthCheckTimeout,
+	}
+	h.mux.HandleFunc("GET /healthz", h.getHealth)
+	h.mux.HandleFunc("GET /readyz", h.getReadiness)
+	h.mux.HandleFunc("GET /api/v1/status", h.getStatus)
+	h.mux.HandleFunc("GET /api/v1/validators", h.getValidators)
+	h.mux.HandleFunc("GET /api/v1/validators/{index}", h.getValidator)
+	h.mux.HandleFunc("GET /api/v1/epochs/{epoch}", h.getEpoch)
+	h.mux.HandleFunc("GET /api/v1/duties/upcoming", h.getUpcomingDuties)
+	h.mux.HandleFunc("GET /api/v1/silences", h.getSilences)
+	h.mux.HandleFunc("POST /api/v1/silences", h.postSilence)
+	h.mux.HandleFunc("DELETE /api/v1/silences/{id}", h.deleteSilence)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+type validatorsResponse struct {
+	Pubkeys []string                `json:"pubkeys"`
+	Indices []domain.ValidatorIndex `json:"indices"`
+	// Tag of each tagged validator, such as solo or lido
+	Tags    map[domain.ValidatorIndex]string `json:"tags,omitempty"`
+	Epoch   domain.Epoch                     `json:"epoch"`
+	Offline []domain.ValidatorIndex          `json:"offline"`
+	// Lifecycle of each pubkey, including the validators that are pending or exited and have no duties
+	Lifecycles map[string]domain.ValidatorLifecycle `json:"lifecycles,omitempty"`
+}
+
+// silenceRequest opens a maintenance window. Notifications are named without the network prefix,
+// such as validator-liveness. The window ends at EndsAt, or Duration after it starts.
+type silenceRequest struct {
+	Notifications []string                `json:"notifications"`
+	Validators    []domain.ValidatorIndex `json:"validators"`
+	StartsAt      time.Time               `json:"startsAt"`
+	EndsAt        time.Time               `json:"endsAt"`
+	Duration      string                  `json:"duration"`
+	Reason        string                  `json:"reason"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// getHealth fails only when the checker is stuck, a restart will not fix unhealthy dependencies.
+// It does not query the dependencies, so it can be probed often.
+func (h *Handler) getHealth(w http.ResponseWriter, _ *http.Request) {
+	report := h.health.Live()
+	code := http.StatusOK
+	if !report.Live {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+// getReadiness fails whenever the tracker cannot currently judge validators
+func (h *Handler) getReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.healthCheckTimeout)
+	defer cancel()
+	report := h.health.Check(ctx)
+	code := http.StatusOK
+	if !report.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+func (h *Handler) getStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.source.Status())
+}
+
+func (h *Handler) getValidators(w http.ResponseWriter, _ *http.Request) {
+	status := h.source.Status()
+	resp := validatorsResponse{
+		Pubkeys:    status.Pubkeys,
+		Indices:    status.Indices,
+		Tags:       status.Tags,
+		Lifecycles: status.Lifecycles,
+	}
+	if status.LastResult != nil {
+		resp.Epoch = status.LastResult.Epoch
+		resp.Offline = status.LastResult.Offline
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getValidator(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.ParseUint(r.PathValue("index"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid validator index"})
+		return
+	}
+
+	status := h.source.Status()
+	lifecycle, hasLifecycle := status.Lifecycle(domain.ValidatorIndex(index))
+	// Validators that are not active have no duties checked, but their lifecycle is still known
+	var validator domain.ValidatorStatus
+	found := false
+	if status.LastResult != nil {
+		validator, found = status.LastResult.Validator(domain.ValidatorIndex(index))
+	}
+	if !found && !hasLifecycle {
+		if status.LastResult == nil {
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: "no epoch checked yet"})
+			return
+		}
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "validator not tracked"})
+		return
+	}
+	validator.Index = domain.ValidatorIndex(index)
+	validator.Tag = status.Tags[validator.Index]
+	if hasLifecycle {
+		validator.Lifecycle = &lifecycle
+	}
+	writeJSON(w, http.StatusOK, validator)
+}
+
+func (h *Handler) getEpoch(w http.ResponseWriter, r *http.Request) {
+	epoch, err := strconv.ParseUint(r.PathValue("epoch"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid epoch"})
+		return
+	}
+
+	result, found, err := h.source.EpochResult(domain.Epoch(epoch))
+	if err != nil {
+		logger.Warn("Error reading results for epoch %d: %v", epoch, err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "failed to read epoch results"})
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "epoch not checked"})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) getUpcomingDuties(w http.ResponseWriter, _ *http.Request) {
+	forecast := h.forecast.Forecast()
+	if forecast.UpdatedAt.IsZero() {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "no forecast available yet"})
+		return
+	}
+	writeJSON(w, http.StatusOK, forecast)
+}
+
+func (h *Handler) getSilences(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.silences.List())
+}
+
+func (h *Handler) postSilence(w http.ResponseWriter, r *http.Request) {
+	var req silenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request body"})
+		return
+	}
+
+	silence := domain.Silence{
+		Validators: req.Validators,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Reason:     req.Reason,
+	}
+	for _, name := range req.Notifications {
+		notification, found := domain.Notifications.ByName(name)
+		if !found {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "unknown notification " + name})
+			return
+		}
+		silence.Notifications = append(silence.Notifications, notification)
+	}
+	if req.Duration != "" {
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || !req.EndsAt.IsZero() {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "duration must be a duration such as 2h, and cannot be combined with endsAt"})
+			return
+		}
+		if silence.StartsAt.IsZero() {
+			silence.StartsAt = time.Now()
+		}
+		silence.EndsAt = silence.StartsAt.Add(duration)
+	}
+
+	silence, err := h.silences.Add(silence)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, silence)
+}
+
+func (h *Handler) deleteSilence(w http.ResponseWriter, r *http.Request) {
+	if !h.silences.Remove(r.Context(), r.PathValue("id")) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "maintenance window not found"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Warn("Error encoding API response: %v", err)
+	}
+}
@@ -1,50 +1,108 @@
 package beacon
 
 import (
+	"cmp"
 	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/dappnode/validator-tracker/internal/application/domain"
 	"github.com/dappnode/validator-tracker/internal/application/ports"
 	"github.com/dappnode/validator-tracker/internal/logger"
+	"github.com/dappnode/validator-tracker/internal/metrics"
 	"github.com/rs/zerolog"
 
 	"github.com/attestantio/go-eth2-client/api"
 	_http "github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/electra"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
+// Backoff between connection attempts while the beacon node is unreachable
+const (
+	minConnectBackoff = 1 * time.Second
+	maxConnectBackoff = 1 * time.Minute
+)
+
 type beaconAttestantClient struct {
+	endpoint   string
+	httpClient *http.Client
+
+	// nil until the first successful connection, afterwards the attestant client handles reconnections itself
+	mu     sync.RWMutex
 	client *_http.Service
 }
 
-func NewBeaconAdapter(endpoint string) (ports.BeaconChainAdapter, error) {
+// NewBeaconAdapter returns a disconnected adapter that keeps trying to connect to the beacon node in the
+// background until the context is done. Calls made before the connection succeeds return ports.ErrBeaconUnavailable.
+func NewBeaconAdapter(ctx context.Context, endpoint string, timeout time.Duration) ports.BeaconChainAdapter {
 	zerolog.SetGlobalLevel(zerolog.WarnLevel)
 
-	customHttpClient := &http.Client{
-		Timeout: 20 * time.Second,
+	b := &beaconAttestantClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: metrics.NewTransport(metrics.ComponentBeacon, nil),
+		},
 	}
+	go b.connect(ctx)
+	return b
+}
 
-	client, err := _http.New(context.Background(),
-		_http.WithAddress(endpoint),
-		_http.WithHTTPClient(customHttpClient),
-		_http.WithTimeout(20*time.Second), // important as attestant API overrides my timeout TODO: investigate how
-	)
-	if err != nil {
-		return nil, err
+// connect retries the connection with exponential backoff until it succeeds or the context is done
+func (b *beaconAttestantClient) connect(ctx context.Context) {
+	backoff := minConnectBackoff
+	for {
+		client, err := _http.New(ctx,
+			_http.WithAddress(b.endpoint),
+			_http.WithHTTPClient(b.httpClient),
+			_http.WithTimeout(b.httpClient.Timeout), // important as attestant API overrides my timeout TODO: investigate how
+		)
+		if err == nil {
+			b.mu.Lock()
+			b.client = client.(*_http.Service)
+			b.mu.Unlock()
+			logger.Info("Connected to beacon node at %s", b.endpoint)
+			return
+		}
+
+		logger.Warn("Beacon node at %s unavailable, retrying in %s: %v", b.endpoint, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
 	}
+}
 
-	return &beaconAttestantClient{client: client.(*_http.Service)}, nil
+// service returns the attestant client, or ports.ErrBeaconUnavailable if it is not connected yet
+func (b *beaconAttestantClient) service() (*_http.Service, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.client == nil {
+		return nil, ports.ErrBeaconUnavailable
+	}
+	return b.client, nil
 }
 
 // GetFinalizedEpoch retrieves the latest finalized epoch from the beacon chain.
 func (b *beaconAttestantClient) GetFinalizedEpoch(ctx context.Context) (domain.Epoch, error) {
-	finality, err := b.client.Finality(ctx, &api.FinalityOpts{State: "head"})
+	client, err := b.service()
+	if err != nil {
+		return 0, err
+	}
+	finality, err := client.Finality(ctx, &api.FinalityOpts{State: "head"})
 	if err != nil {
 		return 0, err
 	}
@@ -53,7 +111,11 @@ func (b *beaconAttestantClient) GetFinalizedEpoch(ctx context.Context) (domain.E
 
 // GetJustifiedEpoch retrieves the latest finalized epoch from the beacon chain.
 func (b *beaconAttestantClient) GetJustifiedEpoch(ctx context.Context) (domain.Epoch, error) {
-	finality, err := b.client.Finality(ctx, &api.FinalityOpts{State: "head"})
+	client, err := b.service()
+	if err != nil {
+		return 0, err
+	}
+	finality, err := client.Finality(ctx, &api.FinalityOpts{State: "head"})
 	if err != nil {
 		return 0, err
 	}
@@ -71,7 +133,11 @@ func (b *beaconAttestantClient) GetValidatorDutiesBatch(ctx context.Context, epo
 		indices[i] = phase0.ValidatorIndex(idx)
 	}
 
-	duties, err := b.client.AttesterDuties(ctx, &api.AttesterDutiesOpts{
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	duties, err := client.AttesterDuties(ctx, &api.AttesterDutiesOpts{
 		Epoch:   phase0.Epoch(epoch),
 		Indices: indices,
 	})
@@ -94,7 +160,11 @@ func (b *beaconAttestantClient) GetValidatorDutiesBatch(ctx context.Context, epo
 }
 
 func (b *beaconAttestantClient) GetValidatorDuties(ctx context.Context, epoch domain.Epoch, validatorIndex domain.ValidatorIndex) (domain.ValidatorDuty, error) {
-	duties, err := b.client.AttesterDuties(ctx, &api.AttesterDutiesOpts{
+	client, err := b.service()
+	if err != nil {
+		return domain.ValidatorDuty{}, err
+	}
+	duties, err := client.AttesterDuties(ctx, &api.AttesterDutiesOpts{
 		Epoch:   phase0.Epoch(epoch),
 		Indices: []phase0.ValidatorIndex{phase0.ValidatorIndex(validatorIndex)},
 	})
@@ -115,47 +185,179 @@ func (b *beaconAttestantClient) GetValidatorDuties(ctx context.Context, epoch do
 	}, nil
 }
 
-// GetCommitteeSizeMap retrieves the size of each attestation committee for a specific slot.
-// This is very expensive and take a long time to execute, so it should be used sparingly.
-// TODO: can we get rid of this?
-func (b *beaconAttestantClient) GetCommitteeSizeMap(ctx context.Context, slot domain.Slot) (domain.CommitteeSizeMap, error) {
-	committees, err := b.client.BeaconCommittees(ctx, &api.BeaconCommitteesOpts{
-		State: fmt.Sprintf("%d", slot),
+// GetEpochCommitteeSizes retrieves the size of each attestation committee of every slot of an epoch.
+// This is expensive, as the beacon node computes the committees from the state at the start of the epoch,
+// so it is done once per epoch.
+func (b *beaconAttestantClient) GetEpochCommitteeSizes(ctx context.Context, epoch domain.Epoch) (map[domain.Slot]domain.CommitteeSizeMap, error) {
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	slotsPerEpoch, err := client.SlotsPerEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	beaconEpoch := phase0.Epoch(epoch)
+	committees, err := client.BeaconCommittees(ctx, &api.BeaconCommitteesOpts{
+		State: fmt.Sprintf("%d", uint64(epoch)*slotsPerEpoch),
+		Epoch: &beaconEpoch,
 	})
 	if err != nil {
 		return nil, err
 	}
-	sizeMap := make(domain.CommitteeSizeMap)
+	sizes := make(map[domain.Slot]domain.CommitteeSizeMap)
 	for _, committee := range committees.Data {
-		if domain.Slot(committee.Slot) != slot {
-			continue
+		slot := domain.Slot(committee.Slot)
+		if sizes[slot] == nil {
+			sizes[slot] = make(domain.CommitteeSizeMap)
 		}
-		sizeMap[domain.CommitteeIndex(committee.Index)] = len(committee.Validators)
+		sizes[slot][domain.CommitteeIndex(committee.Index)] = len(committee.Validators)
+	}
+	return sizes, nil
+}
+
+// GetJustifiedCheckpoint retrieves the current justified checkpoint of the state at a slot, which the
+// attestations of the epoch of that slot must vote for as source
+func (b *beaconAttestantClient) GetJustifiedCheckpoint(ctx context.Context, slot domain.Slot) (domain.Checkpoint, error) {
+	client, err := b.service()
+	if err != nil {
+		return domain.Checkpoint{}, err
+	}
+	finality, err := client.Finality(ctx, &api.FinalityOpts{State: fmt.Sprintf("%d", slot)})
+	if err != nil {
+		return domain.Checkpoint{}, err
 	}
-	return sizeMap, nil
+	return domain.Checkpoint{
+		Epoch: domain.Epoch(finality.Data.Justified.Epoch),
+		Root:  domain.Root(finality.Data.Justified.Root),
+	}, nil
 }
 
-// GetBlockAttestations retrieves all attestations include in a slot
+// GetBlockAttestations retrieves all attestations include in a slot.
+// Returns an empty slice if there is no block at the given slot.
 func (b *beaconAttestantClient) GetBlockAttestations(ctx context.Context, slot domain.Slot) ([]domain.Attestation, error) {
-	block, err := b.client.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{
-		Block: fmt.Sprintf("%d", slot),
-	})
+	client, err := b.service()
 	if err != nil {
 		return nil, err
 	}
+	block, err := b.signedBlock(ctx, client, slot)
+	if err != nil || block == nil {
+		return nil, err
+	}
 
 	var attestations []domain.Attestation
-	for _, att := range block.Data.Electra.Message.Body.Attestations {
+	for _, att := range block.Message.Body.Attestations {
 		attestations = append(attestations, domain.Attestation{
 			DataSlot:        domain.Slot(att.Data.Slot),
 			CommitteeBits:   att.CommitteeBits,
 			AggregationBits: att.AggregationBits,
+			BeaconBlockRoot: domain.Root(att.Data.BeaconBlockRoot),
+			SourceEpoch:     domain.Epoch(att.Data.Source.Epoch),
+			SourceRoot:      domain.Root(att.Data.Source.Root),
+			TargetEpoch:     domain.Epoch(att.Data.Target.Epoch),
+			TargetRoot:      domain.Root(att.Data.Target.Root),
 		})
 	}
 	return attestations, nil
 }
 
-func (b *beaconAttestantClient) GetValidatorIndicesByPubkeys(ctx context.Context, pubkeys []string) ([]domain.ValidatorIndex, error) {
+// GetBlockRoot retrieves the root of the canonical block at a slot. The boolean is false if the slot is empty.
+func (b *beaconAttestantClient) GetBlockRoot(ctx context.Context, slot domain.Slot) (domain.Root, bool, error) {
+	client, err := b.service()
+	if err != nil {
+		return domain.Root{}, false, err
+	}
+	root, err := client.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{
+		Block: fmt.Sprintf("%d", slot),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return domain.Root{}, false, nil
+		}
+		return domain.Root{}, false, err
+	}
+	return domain.Root(*root.Data), true, nil
+}
+
+// GetSlotsPerEpoch retrieves the SLOTS_PER_EPOCH value of the network the beacon node is connected to.
+func (b *beaconAttestantClient) GetSlotsPerEpoch(ctx context.Context) (uint64, error) {
+	client, err := b.service()
+	if err != nil {
+		return 0, err
+	}
+	return client.SlotsPerEpoch(ctx)
+}
+
+// GetChainSpec retrieves the genesis time, the timing parameters and the fork schedule of the chain spec.
+// Forks scheduled at the far future epoch, which are not planned yet, are left out.
+func (b *beaconAttestantClient) GetChainSpec(ctx context.Context) (domain.ChainSpec, error) {
+	client, err := b.service()
+	if err != nil {
+		return domain.ChainSpec{}, err
+	}
+	genesis, err := client.Genesis(ctx, &api.GenesisOpts{})
+	if err != nil {
+		return domain.ChainSpec{}, err
+	}
+	slotDuration, err := client.SlotDuration(ctx)
+	if err != nil {
+		return domain.ChainSpec{}, err
+	}
+	slotsPerEpoch, err := client.SlotsPerEpoch(ctx)
+	if err != nil {
+		return domain.ChainSpec{}, err
+	}
+	spec, err := client.Spec(ctx, &api.SpecOpts{})
+	if err != nil {
+		return domain.ChainSpec{}, err
+	}
+	epochsPerPeriod, ok := spec.Data["EPOCHS_PER_SYNC_COMMITTEE_PERIOD"].(uint64)
+	if !ok || epochsPerPeriod == 0 || slotsPerEpoch == 0 {
+		return domain.ChainSpec{}, errors.New("invalid EPOCHS_PER_SYNC_COMMITTEE_PERIOD or SLOTS_PER_EPOCH in the chain spec")
+	}
+
+	chainSpec := domain.ChainSpec{
+		ChainTiming: domain.ChainTiming{
+			GenesisTime:                  genesis.Data.GenesisTime,
+			SlotDuration:                 slotDuration,
+			SlotsPerEpoch:                slotsPerEpoch,
+			EpochsPerSyncCommitteePeriod: epochsPerPeriod,
+		},
+		Forks: []domain.Fork{{Name: "phase0"}},
+	}
+	chainSpec.ConfigName, _ = spec.Data["CONFIG_NAME"].(string)
+	if churn, ok := spec.Data["MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT"].(uint64); ok {
+		chainSpec.DepositChurnLimit = domain.Gwei(churn)
+	}
+	chainSpec.MaxPendingDepositsPerEpoch, _ = spec.Data["MAX_PENDING_DEPOSITS_PER_EPOCH"].(uint64)
+	for key, value := range spec.Data {
+		name, isFork := strings.CutSuffix(key, "_FORK_EPOCH")
+		epoch, ok := value.(uint64)
+		if !isFork || !ok || epoch == math.MaxUint64 {
+			continue
+		}
+		chainSpec.Forks = append(chainSpec.Forks, domain.Fork{Name: strings.ToLower(name), Epoch: domain.Epoch(epoch)})
+	}
+	// Devnets often activate several forks at the same epoch, those are sorted by their order in the upgrade
+	// history. Forks newer than the known ones come last.
+	forkOrder := func(name string) int {
+		if i := slices.Index(forkHistory, name); i >= 0 {
+			return i
+		}
+		return len(forkHistory)
+	}
+	slices.SortFunc(chainSpec.Forks, func(a, c domain.Fork) int {
+		return cmp.Or(cmp.Compare(a.Epoch, c.Epoch), cmp.Compare(forkOrder(a.Name), forkOrder(c.Name)), strings.Compare(a.Name, c.Name))
+	})
+	return chainSpec, nil
+}
+
+// forkHistory lists the forks in the order they activate
+var forkHistory = []string{"phase0", "altair", "bellatrix", "capella", "deneb", "electra", "fulu"}
+
+// GetValidatorIndicesByPubkeys returns the index of each validator by its normalized pubkey, whatever its status.
+// Pubkeys whose deposit was not processed yet have no index and are missing.
+func (b *beaconAttestantClient) GetValidatorIndicesByPubkeys(ctx context.Context, pubkeys []string) (map[string]domain.ValidatorIndex, error) {
 	if len(pubkeys) == 0 {
 		logger.Debug("Called GetValidatorIndicesByPubkeys with no pubkeys, nothing to check")
 		return nil, nil
@@ -181,28 +383,127 @@ func (b *beaconAttestantClient) GetValidatorIndicesByPubkeys(ctx context.Context
 		beaconPubkeys = append(beaconPubkeys, blsPubkey)
 	}
 
-	// Only get validators in active states
-	// TODO: why do I need apiv1 for this struct? is there something newer?
-	validators, err := b.client.Validators(ctx, &api.ValidatorsOpts{
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	validators, err := getValidators(ctx, client, api.ValidatorsOpts{
 		State:   "justified",
 		PubKeys: beaconPubkeys,
-		ValidatorStates: []v1.ValidatorState{
-			v1.ValidatorStateActiveOngoing,
-			v1.ValidatorStateActiveExiting,
-			v1.ValidatorStateActiveSlashed,
-		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	var indices []domain.ValidatorIndex
-	for _, v := range validators.Data {
-		indices = append(indices, domain.ValidatorIndex(v.Index))
+	indices := make(map[string]domain.ValidatorIndex, len(validators))
+	for _, v := range validators {
+		if v.Validator == nil {
+			continue
+		}
+		indices[domain.NormalizePubkey(v.Validator.PublicKey.String())] = domain.ValidatorIndex(v.Index)
 	}
 	return indices, nil
 }
 
+// GetValidatorStates returns the status and the lifecycle epochs of each validator, whatever its status
+func (b *beaconAttestantClient) GetValidatorStates(ctx context.Context, indices []domain.ValidatorIndex) (map[domain.ValidatorIndex]domain.ValidatorState, error) {
+	if len(indices) == 0 {
+		logger.Debug("Called GetValidatorStates with no validator indices, nothing to check")
+		return nil, nil
+	}
+
+	beaconIndices := make([]phase0.ValidatorIndex, len(indices))
+	for i, idx := range indices {
+		beaconIndices[i] = phase0.ValidatorIndex(idx)
+	}
+
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	validators, err := getValidators(ctx, client, api.ValidatorsOpts{
+		State:   "justified",
+		Indices: beaconIndices,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[domain.ValidatorIndex]domain.ValidatorState, len(validators))
+	for index, v := range validators {
+		if v.Validator == nil {
+			continue
+		}
+		states[domain.ValidatorIndex(index)] = domain.ValidatorState{
+			Index:                      domain.ValidatorIndex(index),
+			Status:                     domain.LifecycleStatus(v.Status.String()),
+			Balance:                    domain.Gwei(v.Balance),
+			Slashed:                    v.Validator.Slashed,
+			ActivationEligibilityEpoch: domain.Epoch(v.Validator.ActivationEligibilityEpoch),
+			ActivationEpoch:            domain.Epoch(v.Validator.ActivationEpoch),
+			ExitEpoch:                  domain.Epoch(v.Validator.ExitEpoch),
+			WithdrawableEpoch:          domain.Epoch(v.Validator.WithdrawableEpoch),
+		}
+	}
+	return states, nil
+}
+
+// GetPendingDeposits returns the pending deposits queue of the justified state, in processing order.
+// Beacon nodes only serve it since electra.
+func (b *beaconAttestantClient) GetPendingDeposits(ctx context.Context) ([]domain.PendingDeposit, error) {
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.PendingDeposits(ctx, &api.PendingDepositsOpts{State: "justified"})
+	if err != nil {
+		return nil, err
+	}
+	deposits := make([]domain.PendingDeposit, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		deposits = append(deposits, domain.PendingDeposit{
+			Pubkey: domain.NormalizePubkey(d.Pubkey.String()),
+			Amount: domain.Gwei(d.Amount),
+		})
+	}
+	return deposits, nil
+}
+
+// validatorsChunkSize bounds the validators asked for in a single request, as beacon nodes limit the size
+// of the request body
+const validatorsChunkSize = 1000
+
+// getValidators queries the validators by index or pubkey in chunks of validatorsChunkSize, merging the results.
+// Unlike the client, it returns no validator instead of the whole state if opts has no index nor pubkey.
+func getValidators(ctx context.Context, client *_http.Service, opts api.ValidatorsOpts) (map[phase0.ValidatorIndex]*v1.Validator, error) {
+	validators := make(map[phase0.ValidatorIndex]*v1.Validator)
+	query := func(chunk api.ValidatorsOpts) error {
+		resp, err := client.Validators(ctx, &chunk)
+		if err != nil {
+			return err
+		}
+		maps.Copy(validators, resp.Data)
+		return nil
+	}
+
+	indices, pubkeys := opts.Indices, opts.PubKeys
+	opts.Indices, opts.PubKeys = nil, nil
+	for chunk := range slices.Chunk(indices, validatorsChunkSize) {
+		opts.Indices = chunk
+		if err := query(opts); err != nil {
+			return nil, err
+		}
+	}
+	opts.Indices = nil
+	for chunk := range slices.Chunk(pubkeys, validatorsChunkSize) {
+		opts.PubKeys = chunk
+		if err := query(opts); err != nil {
+			return nil, err
+		}
+	}
+	return validators, nil
+}
+
 // GetProposerDuties retrieves proposer duties for the given epoch and validator indices.
 func (b *beaconAttestantClient) GetProposerDuties(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.ProposerDuty, error) {
 	if len(indices) == 0 {
@@ -215,7 +516,11 @@ func (b *beaconAttestantClient) GetProposerDuties(ctx context.Context, epoch dom
 		beaconIndices[i] = phase0.ValidatorIndex(idx)
 	}
 
-	resp, err := b.client.ProposerDuties(ctx, &api.ProposerDutiesOpts{
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.ProposerDuties(ctx, &api.ProposerDutiesOpts{
 		Epoch:   phase0.Epoch(epoch),
 		Indices: beaconIndices,
 	})
@@ -233,20 +538,154 @@ func (b *beaconAttestantClient) GetProposerDuties(ctx context.Context, epoch dom
 	return duties, nil
 }
 
-// DidProposeBlock checks a given slot includes a block proposed
-func (b *beaconAttestantClient) DidProposeBlock(ctx context.Context, slot domain.Slot) (bool, error) {
-	block, err := b.client.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{
+type blockHeadersResponse struct {
+	Data []struct {
+		Root      string `json:"root"`
+		Canonical bool   `json:"canonical"`
+		Header    struct {
+			Message struct {
+				ProposerIndex string `json:"proposer_index"`
+			} `json:"message"`
+		} `json:"header"`
+	} `json:"data"`
+}
+
+// GetSlotBlocks retrieves the headers of every block the beacon node knows at a slot, see
+// https://ethereum.github.io/beacon-APIs/#/Beacon/getBlockHeaders. The attestant client only returns the
+// canonical one. Clients answer an empty slot either with an empty list or with a 404, both mean no block.
+func (b *beaconAttestantClient) GetSlotBlocks(ctx context.Context, slot domain.Slot) ([]domain.SlotBlock, error) {
+	var resp blockHeadersResponse
+	if err := b.getNodeJSON(ctx, fmt.Sprintf("/eth/v1/beacon/headers?slot=%d", slot), &resp); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	blocks := make([]domain.SlotBlock, 0, len(resp.Data))
+	for _, h := range resp.Data {
+		root, err := hex.DecodeString(strings.TrimPrefix(h.Root, "0x"))
+		if err != nil || len(root) != len(domain.Root{}) {
+			return nil, fmt.Errorf("invalid block root %q at slot %d", h.Root, slot)
+		}
+		proposerIndex, err := strconv.ParseUint(h.Header.Message.ProposerIndex, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proposer index %q at slot %d: %w", h.Header.Message.ProposerIndex, slot, err)
+		}
+		blocks = append(blocks, domain.SlotBlock{
+			Root:          domain.Root(root),
+			ProposerIndex: domain.ValidatorIndex(proposerIndex),
+			Canonical:     h.Canonical,
+		})
+	}
+	return blocks, nil
+}
+
+// GetBlockExecutionPayload retrieves the execution payload of the block at a slot. The boolean is false if the slot is empty.
+func (b *beaconAttestantClient) GetBlockExecutionPayload(ctx context.Context, slot domain.Slot) (domain.ExecutionPayload, bool, error) {
+	client, err := b.service()
+	if err != nil {
+		return domain.ExecutionPayload{}, false, err
+	}
+	block, err := b.signedBlock(ctx, client, slot)
+	if err != nil || block == nil {
+		return domain.ExecutionPayload{}, false, err
+	}
+	payload := block.Message.Body.ExecutionPayload
+	if payload == nil {
+		return domain.ExecutionPayload{}, false, fmt.Errorf("block at slot %d has no execution payload", slot)
+	}
+	return domain.ExecutionPayload{
+		BlockNumber:  payload.BlockNumber,
+		BlockHash:    payload.BlockHash.String(),
+		FeeRecipient: payload.FeeRecipient.String(),
+	}, true, nil
+}
+
+// GetSyncCommitteeDuties retrieves the sync committee duties for the given epoch and validator indices.
+func (b *beaconAttestantClient) GetSyncCommitteeDuties(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.SyncCommitteeDuty, error) {
+	if len(indices) == 0 {
+		logger.Debug("Called GetSyncCommitteeDuties with no validator indices, returning empty slice. Nothing to check.")
+		return nil, nil
+	}
+
+	beaconIndices := make([]phase0.ValidatorIndex, len(indices))
+	for i, idx := range indices {
+		beaconIndices[i] = phase0.ValidatorIndex(idx)
+	}
+
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.SyncCommitteeDuties(ctx, &api.SyncCommitteeDutiesOpts{
+		Epoch:   phase0.Epoch(epoch),
+		Indices: beaconIndices,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var duties []domain.SyncCommitteeDuty
+	for _, d := range resp.Data {
+		positions := make([]uint64, len(d.ValidatorSyncCommitteeIndices))
+		for i, pos := range d.ValidatorSyncCommitteeIndices {
+			positions[i] = uint64(pos)
+		}
+		duties = append(duties, domain.SyncCommitteeDuty{
+			ValidatorIndex:                domain.ValidatorIndex(d.ValidatorIndex),
+			ValidatorSyncCommitteeIndices: positions,
+		})
+	}
+	return duties, nil
+}
+
+// GetBlockSyncAggregate retrieves the sync committee bits of the block at a slot. The boolean is false if the slot is empty.
+func (b *beaconAttestantClient) GetBlockSyncAggregate(ctx context.Context, slot domain.Slot) ([]byte, bool, error) {
+	client, err := b.service()
+	if err != nil {
+		return nil, false, err
+	}
+	block, err := b.signedBlock(ctx, client, slot)
+	if err != nil || block == nil {
+		return nil, false, err
+	}
+	if block.Message.Body.SyncAggregate == nil {
+		return nil, false, fmt.Errorf("block at slot %d has no sync aggregate", slot)
+	}
+	return block.Message.Body.SyncAggregate.SyncCommitteeBits, true, nil
+}
+
+// fuluBlockResponse is the JSON answer of the beacon node for a fulu block, whose body is the electra one
+type fuluBlockResponse struct {
+	Version string                     `json:"version"`
+	Data    *electra.SignedBeaconBlock `json:"data"`
+}
+
+// signedBlock retrieves the block at a slot, nil if the slot is empty. Blocks of the forks not in
+// domain.SupportedForks return an error. The attestant client does not decode fulu blocks, those are
+// read from the beacon API directly when the client fails.
+func (b *beaconAttestantClient) signedBlock(ctx context.Context, client *_http.Service, slot domain.Slot) (*electra.SignedBeaconBlock, error) {
+	block, err := client.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{
 		Block: fmt.Sprintf("%d", slot),
 	})
 	if err != nil {
-		// TODO: are we sure we can assume that a 404 means the block was not proposed?
-		// What error code is returned in all consensus if the block is not in their state?
-		if apiErr, ok := err.(*api.Error); ok && apiErr.StatusCode == 404 {
-			return false, nil // Block was not proposed
+		if isNotFound(err) {
+			return nil, nil // Empty slot
 		}
-		return false, err // Real error
+		var fulu fuluBlockResponse
+		if fuluErr := b.getNodeJSON(ctx, fmt.Sprintf("/eth/v2/beacon/blocks/%d", slot), &fulu); fuluErr != nil || fulu.Version != "fulu" {
+			return nil, err
+		}
+		if fulu.Data == nil || fulu.Data.Message == nil || fulu.Data.Message.Body == nil {
+			return nil, fmt.Errorf("incomplete fulu block at slot %d", slot)
+		}
+		return fulu.Data, nil
+	}
+	if block.Data.Electra == nil || block.Data.Electra.Message == nil || block.Data.Electra.Message.Body == nil {
+		return nil, fmt.Errorf("unsupported block version %s at slot %d, blocks are only read for forks %v", block.Data.Version, slot, domain.SupportedForks)
 	}
-	return block != nil && block.Data != nil, nil
+	return block.Data.Electra, nil
 }
 
 func (b *beaconAttestantClient) GetValidatorsLiveness(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) (map[domain.ValidatorIndex]bool, error) {
@@ -260,7 +699,11 @@ func (b *beaconAttestantClient) GetValidatorsLiveness(ctx context.Context, epoch
 		beaconIndices[i] = phase0.ValidatorIndex(idx)
 	}
 
-	liveness, err := b.client.ValidatorLiveness(ctx, &api.ValidatorLivenessOpts{
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	liveness, err := client.ValidatorLiveness(ctx, &api.ValidatorLivenessOpts{
 		Epoch:   phase0.Epoch(epoch),
 		Indices: beaconIndices,
 	})
@@ -289,7 +732,11 @@ func (b *beaconAttestantClient) GetSlashedValidators(ctx context.Context, indice
 		beaconIndices[i] = phase0.ValidatorIndex(idx)
 	}
 
-	slashed, err := b.client.Validators(ctx, &api.ValidatorsOpts{
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	slashed, err := getValidators(ctx, client, api.ValidatorsOpts{
 		State: "justified",
 		// Only get validators in slashed states
 		ValidatorStates: []v1.ValidatorState{
@@ -302,13 +749,57 @@ func (b *beaconAttestantClient) GetSlashedValidators(ctx context.Context, indice
 		return nil, err
 	}
 
-	slashedIndices := make([]domain.ValidatorIndex, 0, len(slashed.Data))
-	for _, v := range slashed.Data {
+	slashedIndices := make([]domain.ValidatorIndex, 0, len(slashed))
+	for _, v := range slashed {
 		slashedIndices = append(slashedIndices, domain.ValidatorIndex(v.Index))
 	}
 	return slashedIndices, nil
 }
 
+// SubscribeChainEvents streams head, block, finalized checkpoint and reorg events until the context is done.
+// The underlying client reconnects on its own when the stream drops. Events are dropped if the consumer
+// falls behind, so consumers must not rely on receiving every single event.
+func (b *beaconAttestantClient) SubscribeChainEvents(ctx context.Context) (<-chan domain.ChainEvent, error) {
+	events := make(chan domain.ChainEvent, 32)
+	send := func(event domain.ChainEvent) {
+		select {
+		case events <- event:
+		default:
+			logger.Debug("Dropping %s event at slot %d, consumer is busy", event.Type, event.Slot)
+		}
+	}
+
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	err = client.Events(ctx, &api.EventsOpts{
+		Topics: []string{"head", "block", "finalized_checkpoint", "chain_reorg"},
+		HeadHandler: func(_ context.Context, e *v1.HeadEvent) {
+			send(domain.ChainEvent{Type: domain.ChainEventHead, Slot: domain.Slot(e.Slot), EpochTransition: e.EpochTransition})
+		},
+		BlockHandler: func(_ context.Context, e *v1.BlockEvent) {
+			send(domain.ChainEvent{Type: domain.ChainEventBlock, Slot: domain.Slot(e.Slot)})
+		},
+		FinalizedCheckpointHandler: func(_ context.Context, e *v1.FinalizedCheckpointEvent) {
+			send(domain.ChainEvent{Type: domain.ChainEventFinalizedCheckpoint, Epoch: domain.Epoch(e.Epoch)})
+		},
+		ChainReorgHandler: func(_ context.Context, e *v1.ChainReorgEvent) {
+			send(domain.ChainEvent{Type: domain.ChainEventReorg, Slot: domain.Slot(e.Slot), Epoch: domain.Epoch(e.Epoch), Depth: e.Depth})
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// isNotFound returns true if the beacon node answered with a 404
+func isNotFound(err error) bool {
+	var apiErr *api.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
 // enum for consensus client
 type ConsensusClient string
 
@@ -323,7 +814,11 @@ const (
 
 // GetConsensusClient see https://ethereum.github.io/beacon-APIs/#/Node/getNodeVersion. Does not throw an error if the client is not available
 func (b *beaconAttestantClient) GetConsensusClient(ctx context.Context) ConsensusClient {
-	resp, err := b.client.NodeClient(ctx)
+	client, err := b.service()
+	if err != nil {
+		return Unknown
+	}
+	resp, err := client.NodeClient(ctx)
 	if err != nil || resp == nil {
 		return Unknown
 	}
@@ -0,0 +1,299 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// multiBeaconAdapter spreads the calls over several beacon nodes. Calls go to the preferred node and fail over
+// to the next ones on error. With cross-checking enabled, liveness and proposals are asked to every node and a
+// validator is only reported offline, or a block missed, if no node saw it.
+type multiBeaconAdapter struct {
+	nodes      []ports.BeaconChainAdapter
+	crossCheck bool
+
+	mu        sync.Mutex
+	preferred int
+}
+
+func NewMultiBeaconAdapter(nodes []ports.BeaconChainAdapter, crossCheck bool) ports.BeaconChainAdapter {
+	return &multiBeaconAdapter{nodes: nodes, crossCheck: crossCheck}
+}
+
+// NewBeaconAdapters creates an adapter per endpoint, wrapped in a multi-node adapter if there are several
+func NewBeaconAdapters(ctx context.Context, endpoints []string, crossCheck bool, timeout time.Duration) ports.BeaconChainAdapter {
+	nodes := make([]ports.BeaconChainAdapter, len(endpoints))
+	for i, endpoint := range endpoints {
+		nodes[i] = NewBeaconAdapter(ctx, endpoint, timeout)
+	}
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return NewMultiBeaconAdapter(nodes, crossCheck)
+}
+
+// failover calls the nodes starting from the preferred one until one succeeds, which becomes the preferred one
+func failover[T any](m *multiBeaconAdapter, call func(node ports.BeaconChainAdapter) (T, error)) (T, error) {
+	m.mu.Lock()
+	start := m.preferred
+	m.mu.Unlock()
+
+	var errs []error
+	for i := range m.nodes {
+		idx := (start + i) % len(m.nodes)
+		result, err := call(m.nodes[idx])
+		if err == nil {
+			m.prefer(idx)
+			return result, nil
+		}
+		logger.Debug("Beacon node %d failed, trying the next one: %v", idx, err)
+		errs = append(errs, err)
+	}
+	var zero T
+	return zero, errors.Join(errs...)
+}
+
+func (m *multiBeaconAdapter) prefer(idx int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.preferred != idx {
+		logger.Info("Switching to beacon node %d", idx)
+		m.preferred = idx
+	}
+}
+
+// withFound carries the extra boolean of the calls that report empty slots
+type withFound[T any] struct {
+	value T
+	found bool
+}
+
+func (m *multiBeaconAdapter) GetFinalizedEpoch(ctx context.Context) (domain.Epoch, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (domain.Epoch, error) { return n.GetFinalizedEpoch(ctx) })
+}
+
+func (m *multiBeaconAdapter) GetJustifiedEpoch(ctx context.Context) (domain.Epoch, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (domain.Epoch, error) { return n.GetJustifiedEpoch(ctx) })
+}
+
+func (m *multiBeaconAdapter) GetValidatorDutiesBatch(ctx context.Context, epoch domain.Epoch, validatorIndices []domain.ValidatorIndex) ([]domain.ValidatorDuty, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) ([]domain.ValidatorDuty, error) {
+		return n.GetValidatorDutiesBatch(ctx, epoch, validatorIndices)
+	})
+}
+
+func (m *multiBeaconAdapter) GetEpochCommitteeSizes(ctx context.Context, epoch domain.Epoch) (map[domain.Slot]domain.CommitteeSizeMap, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (map[domain.Slot]domain.CommitteeSizeMap, error) {
+		return n.GetEpochCommitteeSizes(ctx, epoch)
+	})
+}
+
+func (m *multiBeaconAdapter) GetJustifiedCheckpoint(ctx context.Context, slot domain.Slot) (domain.Checkpoint, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (domain.Checkpoint, error) {
+		return n.GetJustifiedCheckpoint(ctx, slot)
+	})
+}
+
+func (m *multiBeaconAdapter) GetBlockAttestations(ctx context.Context, slot domain.Slot) ([]domain.Attestation, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) ([]domain.Attestation, error) {
+		return n.GetBlockAttestations(ctx, slot)
+	})
+}
+
+func (m *multiBeaconAdapter) GetBlockRoot(ctx context.Context, slot domain.Slot) (domain.Root, bool, error) {
+	r, err := failover(m, func(n ports.BeaconChainAdapter) (withFound[domain.Root], error) {
+		root, found, err := n.GetBlockRoot(ctx, slot)
+		return withFound[domain.Root]{root, found}, err
+	})
+	return r.value, r.found, err
+}
+
+func (m *multiBeaconAdapter) GetSlotsPerEpoch(ctx context.Context) (uint64, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (uint64, error) { return n.GetSlotsPerEpoch(ctx) })
+}
+
+func (m *multiBeaconAdapter) GetChainSpec(ctx context.Context) (domain.ChainSpec, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (domain.ChainSpec, error) { return n.GetChainSpec(ctx) })
+}
+
+// GetSyncStatus prefers a synced node, so a single syncing node does not stop the checks
+func (m *multiBeaconAdapter) GetSyncStatus(ctx context.Context) (domain.SyncStatus, error) {
+	var first *domain.SyncStatus
+	var errs []error
+	for idx, node := range m.nodes {
+		status, err := node.GetSyncStatus(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !status.IsSyncing && !status.IsOptimistic && !status.ElOffline {
+			m.prefer(idx)
+			return status, nil
+		}
+		if first == nil {
+			first = &status
+		}
+	}
+	if first != nil {
+		return *first, nil
+	}
+	return domain.SyncStatus{}, errors.Join(errs...)
+}
+
+func (m *multiBeaconAdapter) GetNodeHealth(ctx context.Context) (domain.NodeHealth, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (domain.NodeHealth, error) { return n.GetNodeHealth(ctx) })
+}
+
+func (m *multiBeaconAdapter) GetPeerCount(ctx context.Context) (uint64, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (uint64, error) { return n.GetPeerCount(ctx) })
+}
+
+func (m *multiBeaconAdapter) GetValidatorIndicesByPubkeys(ctx context.Context, pubkeys []string) (map[string]domain.ValidatorIndex, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (map[string]domain.ValidatorIndex, error) {
+		return n.GetValidatorIndicesByPubkeys(ctx, pubkeys)
+	})
+}
+
+func (m *multiBeaconAdapter) GetValidatorStates(ctx context.Context, indices []domain.ValidatorIndex) (map[domain.ValidatorIndex]domain.ValidatorState, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (map[domain.ValidatorIndex]domain.ValidatorState, error) {
+		return n.GetValidatorStates(ctx, indices)
+	})
+}
+
+func (m *multiBeaconAdapter) GetPendingDeposits(ctx context.Context) ([]domain.PendingDeposit, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) ([]domain.PendingDeposit, error) {
+		return n.GetPendingDeposits(ctx)
+	})
+}
+
+func (m *multiBeaconAdapter) GetSlashedValidators(ctx context.Context, indices []domain.ValidatorIndex) ([]domain.ValidatorIndex, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) ([]domain.ValidatorIndex, error) {
+		return n.GetSlashedValidators(ctx, indices)
+	})
+}
+
+func (m *multiBeaconAdapter) GetProposerDuties(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.ProposerDuty, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) ([]domain.ProposerDuty, error) {
+		return n.GetProposerDuties(ctx, epoch, indices)
+	})
+}
+
+// GetSlotBlocks merges the blocks every node knows when cross-checking, a block is canonical if any node says so.
+// This way a block is only reported missed if no node has it.
+func (m *multiBeaconAdapter) GetSlotBlocks(ctx context.Context, slot domain.Slot) ([]domain.SlotBlock, error) {
+	if !m.crossCheck {
+		return failover(m, func(n ports.BeaconChainAdapter) ([]domain.SlotBlock, error) { return n.GetSlotBlocks(ctx, slot) })
+	}
+
+	var errs []error
+	answered := false
+	var merged []domain.SlotBlock
+	seen := make(map[domain.Root]int)
+	for idx, node := range m.nodes {
+		blocks, err := node.GetSlotBlocks(ctx, slot)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		answered = true
+		if len(blocks) == 0 {
+			logger.Debug("Beacon node %d has no block at slot %d", idx, slot)
+		}
+		for _, block := range blocks {
+			if i, ok := seen[block.Root]; ok {
+				merged[i].Canonical = merged[i].Canonical || block.Canonical
+				continue
+			}
+			seen[block.Root] = len(merged)
+			merged = append(merged, block)
+		}
+	}
+	if !answered {
+		return nil, errors.Join(errs...)
+	}
+	return merged, nil
+}
+
+func (m *multiBeaconAdapter) GetBlockExecutionPayload(ctx context.Context, slot domain.Slot) (domain.ExecutionPayload, bool, error) {
+	r, err := failover(m, func(n ports.BeaconChainAdapter) (withFound[domain.ExecutionPayload], error) {
+		payload, found, err := n.GetBlockExecutionPayload(ctx, slot)
+		return withFound[domain.ExecutionPayload]{payload, found}, err
+	})
+	return r.value, r.found, err
+}
+
+func (m *multiBeaconAdapter) GetSyncCommitteeDuties(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.SyncCommitteeDuty, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) ([]domain.SyncCommitteeDuty, error) {
+		return n.GetSyncCommitteeDuties(ctx, epoch, indices)
+	})
+}
+
+func (m *multiBeaconAdapter) GetBlockSyncAggregate(ctx context.Context, slot domain.Slot) ([]byte, bool, error) {
+	r, err := failover(m, func(n ports.BeaconChainAdapter) (withFound[[]byte], error) {
+		bits, found, err := n.GetBlockSyncAggregate(ctx, slot)
+		return withFound[[]byte]{bits, found}, err
+	})
+	return r.value, r.found, err
+}
+
+// GetValidatorsLiveness reports a validator offline only if no node saw it when cross-checking
+func (m *multiBeaconAdapter) GetValidatorsLiveness(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) (map[domain.ValidatorIndex]bool, error) {
+	if !m.crossCheck {
+		return failover(m, func(n ports.BeaconChainAdapter) (map[domain.ValidatorIndex]bool, error) {
+			return n.GetValidatorsLiveness(ctx, epoch, indices)
+		})
+	}
+
+	var errs []error
+	var merged map[domain.ValidatorIndex]bool
+	for _, node := range m.nodes {
+		liveness, err := node.GetValidatorsLiveness(ctx, epoch, indices)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if merged == nil {
+			merged = make(map[domain.ValidatorIndex]bool, len(liveness))
+		}
+		for idx, live := range liveness {
+			merged[idx] = merged[idx] || live
+		}
+	}
+	if merged == nil {
+		return nil, errors.Join(errs...)
+	}
+	return merged, nil
+}
+
+func (m *multiBeaconAdapter) GetAttestationRewards(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.ValidatorRewards, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) ([]domain.ValidatorRewards, error) {
+		return n.GetAttestationRewards(ctx, epoch, indices)
+	})
+}
+
+func (m *multiBeaconAdapter) GetBlockReward(ctx context.Context, slot domain.Slot) (domain.BlockReward, bool, error) {
+	r, err := failover(m, func(n ports.BeaconChainAdapter) (withFound[domain.BlockReward], error) {
+		reward, found, err := n.GetBlockReward(ctx, slot)
+		return withFound[domain.BlockReward]{reward, found}, err
+	})
+	return r.value, r.found, err
+}
+
+func (m *multiBeaconAdapter) GetSyncCommitteeRewards(ctx context.Context, slot domain.Slot, indices []domain.ValidatorIndex) ([]domain.SyncCommitteeReward, bool, error) {
+	r, err := failover(m, func(n ports.BeaconChainAdapter) (withFound[[]domain.SyncCommitteeReward], error) {
+		rewards, found, err := n.GetSyncCommitteeRewards(ctx, slot, indices)
+		return withFound[[]domain.SyncCommitteeReward]{rewards, found}, err
+	})
+	return r.value, r.found, err
+}
+
+// SubscribeChainEvents subscribes to a single node, duplicated events from several nodes would only repeat checks
+func (m *multiBeaconAdapter) SubscribeChainEvents(ctx context.Context) (<-chan domain.ChainEvent, error) {
+	return failover(m, func(n ports.BeaconChainAdapter) (<-chan domain.ChainEvent, error) { return n.SubscribeChainEvents(ctx) })
+}
@@ -0,0 +1,123 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+)
+
+// The node endpoints are queried directly, the attestant client does not expose
+// el_offline nor the health and peer count endpoints.
+
+type nodeSyncingResponse struct {
+	Data struct {
+		HeadSlot     string `json:"head_slot"`
+		SyncDistance string `json:"sync_distance"`
+		IsSyncing    bool   `json:"is_syncing"`
+		IsOptimistic bool   `json:"is_optimistic"`
+		ElOffline    bool   `json:"el_offline"`
+	} `json:"data"`
+}
+
+type nodePeerCountResponse struct {
+	Data struct {
+		Connected string `json:"connected"`
+	} `json:"data"`
+}
+
+// GetSyncStatus retrieves the sync state of the beacon node
+func (b *beaconAttestantClient) GetSyncStatus(ctx context.Context) (domain.SyncStatus, error) {
+	var resp nodeSyncingResponse
+	if err := b.getNodeJSON(ctx, "/eth/v1/node/syncing", &resp); err != nil {
+		return domain.SyncStatus{}, err
+	}
+	headSlot, err := strconv.ParseUint(resp.Data.HeadSlot, 10, 64)
+	if err != nil {
+		return domain.SyncStatus{}, fmt.Errorf("invalid head slot %q: %w", resp.Data.HeadSlot, err)
+	}
+	syncDistance, err := strconv.ParseUint(resp.Data.SyncDistance, 10, 64)
+	if err != nil {
+		return domain.SyncStatus{}, fmt.Errorf("invalid sync distance %q: %w", resp.Data.SyncDistance, err)
+	}
+	return domain.SyncStatus{
+		HeadSlot:     domain.Slot(headSlot),
+		SyncDistance: domain.Slot(syncDistance),
+		IsSyncing:    resp.Data.IsSyncing,
+		IsOptimistic: resp.Data.IsOptimistic,
+		ElOffline:    resp.Data.ElOffline,
+	}, nil
+}
+
+// GetNodeHealth see https://ethereum.github.io/beacon-APIs/#/Node/getHealth. The answer is only in the status code.
+func (b *beaconAttestantClient) GetNodeHealth(ctx context.Context) (domain.NodeHealth, error) {
+	if _, err := b.service(); err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.endpoint+"/eth/v1/node/health", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create node health request: %w", err)
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get node health: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return domain.NodeHealthReady, nil
+	case http.StatusPartialContent:
+		return domain.NodeHealthSyncing, nil
+	case http.StatusServiceUnavailable:
+		return domain.NodeHealthNotInitialized, nil
+	default:
+		return "", fmt.Errorf("unexpected node health status %d", resp.StatusCode)
+	}
+}
+
+// GetPeerCount retrieves the number of peers the beacon node is connected to
+func (b *beaconAttestantClient) GetPeerCount(ctx context.Context) (uint64, error) {
+	var resp nodePeerCountResponse
+	if err := b.getNodeJSON(ctx, "/eth/v1/node/peer_count", &resp); err != nil {
+		return 0, err
+	}
+	connected, err := strconv.ParseUint(resp.Data.Connected, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid peer count %q: %w", resp.Data.Connected, err)
+	}
+	return connected, nil
+}
+
+// errNotFound is returned by getNodeJSON when the beacon node answers with a 404
+var errNotFound = errors.New("not found")
+
+func (b *beaconAttestantClient) getNodeJSON(ctx context.Context, path string, out any) error {
+	if _, err := b.service(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.endpoint+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request for %s: %w", path, err)
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", path, errNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d for %s", resp.StatusCode, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
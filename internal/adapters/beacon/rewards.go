@@ -0,0 +1,143 @@
+package beacon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// GetAttestationRewards retrieves the attestation rewards of an epoch. The beacon node returns the ideal rewards
+// per effective balance, so they are matched with the effective balance of each validator in the justified state.
+func (b *beaconAttestantClient) GetAttestationRewards(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.ValidatorRewards, error) {
+	if len(indices) == 0 {
+		logger.Debug("Called GetAttestationRewards with no validator indices, returning empty slice. Nothing to check.")
+		return nil, nil
+	}
+
+	beaconIndices := make([]phase0.ValidatorIndex, len(indices))
+	for i, idx := range indices {
+		beaconIndices[i] = phase0.ValidatorIndex(idx)
+	}
+
+	client, err := b.service()
+	if err != nil {
+		return nil, err
+	}
+	rewards, err := client.AttestationRewards(ctx, &api.AttestationRewardsOpts{
+		Epoch:   phase0.Epoch(epoch),
+		Indices: beaconIndices,
+	})
+	if err != nil {
+		return nil, err
+	}
+	validators, err := getValidators(ctx, client, api.ValidatorsOpts{
+		State:   "justified",
+		Indices: beaconIndices,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ideal := make(map[phase0.Gwei]domain.Gwei, len(rewards.Data.IdealRewards))
+	for _, r := range rewards.Data.IdealRewards {
+		total := domain.Gwei(r.Head) + domain.Gwei(r.Target) + domain.Gwei(r.Source)
+		if r.InclusionDelay != nil {
+			total += domain.Gwei(*r.InclusionDelay)
+		}
+		ideal[r.EffectiveBalance] = total
+	}
+
+	result := make([]domain.ValidatorRewards, 0, len(rewards.Data.TotalRewards))
+	for _, r := range rewards.Data.TotalRewards {
+		earned := domain.Gwei(r.Head) + domain.Gwei(r.Target) + domain.Gwei(r.Source) - domain.Gwei(r.Inactivity)
+		if r.InclusionDelay != nil {
+			earned += domain.Gwei(*r.InclusionDelay)
+		}
+		var effectiveBalance phase0.Gwei
+		if v, ok := validators[r.ValidatorIndex]; ok && v.Validator != nil {
+			effectiveBalance = v.Validator.EffectiveBalance
+		}
+		result = append(result, domain.ValidatorRewards{
+			ValidatorIndex: domain.ValidatorIndex(r.ValidatorIndex),
+			Earned:         earned,
+			Ideal:          idealForBalance(ideal, effectiveBalance),
+		})
+	}
+	return result, nil
+}
+
+// idealForBalance returns the ideal reward of the highest effective balance not above the given one, as beacon
+// nodes only list the effective balances they consider relevant
+func idealForBalance(ideal map[phase0.Gwei]domain.Gwei, effectiveBalance phase0.Gwei) domain.Gwei {
+	var best phase0.Gwei
+	var reward domain.Gwei
+	for balance, r := range ideal {
+		if balance <= effectiveBalance && balance >= best {
+			best = balance
+			reward = r
+		}
+	}
+	return reward
+}
+
+// GetBlockReward retrieves the consensus reward of the block at a slot. The boolean is false if the slot is empty.
+func (b *beaconAttestantClient) GetBlockReward(ctx context.Context, slot domain.Slot) (domain.BlockReward, bool, error) {
+	client, err := b.service()
+	if err != nil {
+		return domain.BlockReward{}, false, err
+	}
+	reward, err := client.BlockRewards(ctx, &api.BlockRewardsOpts{
+		Block: fmt.Sprintf("%d", slot),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return domain.BlockReward{}, false, nil // Empty slot
+		}
+		return domain.BlockReward{}, false, err
+	}
+	return domain.BlockReward{
+		ProposerIndex: domain.ValidatorIndex(reward.Data.ProposerIndex),
+		Total:         domain.Gwei(reward.Data.Total),
+	}, true, nil
+}
+
+// GetSyncCommitteeRewards retrieves the sync committee rewards of the block at a slot. The boolean is false if the slot is empty.
+func (b *beaconAttestantClient) GetSyncCommitteeRewards(ctx context.Context, slot domain.Slot, indices []domain.ValidatorIndex) ([]domain.SyncCommitteeReward, bool, error) {
+	if len(indices) == 0 {
+		logger.Debug("Called GetSyncCommitteeRewards with no validator indices, returning empty slice. Nothing to check.")
+		return nil, true, nil
+	}
+
+	beaconIndices := make([]phase0.ValidatorIndex, len(indices))
+	for i, idx := range indices {
+		beaconIndices[i] = phase0.ValidatorIndex(idx)
+	}
+
+	client, err := b.service()
+	if err != nil {
+		return nil, false, err
+	}
+	rewards, err := client.SyncCommitteeRewards(ctx, &api.SyncCommitteeRewardsOpts{
+		Block:   fmt.Sprintf("%d", slot),
+		Indices: beaconIndices,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, false, nil // Empty slot
+		}
+		return nil, false, err
+	}
+
+	result := make([]domain.SyncCommitteeReward, 0, len(rewards.Data))
+	for _, r := range rewards.Data {
+		result = append(result, domain.SyncCommitteeReward{
+			ValidatorIndex: domain.ValidatorIndex(r.ValidatorIndex),
+			Reward:         domain.Gwei(r.Reward),
+		})
+	}
+	return result, true, nil
+}
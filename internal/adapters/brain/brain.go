@@ -9,7 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dappnode/validator-tracker/internal/application/domain"
 	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/metrics"
 )
 
 // This adapter is required to be used due to the web3signer blocklisting any host requesting its API that is not whitelisted.
@@ -20,9 +22,10 @@ type BrainAdapter struct {
 	client  *http.Client
 }
 
+// brainValidatorsResponse groups the pubkeys by tag, the staking protocol they are used for such as solo or lido
 type brainValidatorsResponse map[string][]string
 
-func NewBrainAdapter(baseURL string) ports.BrainAdapter {
+func NewBrainAdapter(baseURL string, timeout time.Duration) ports.KeySource {
 	// Always append :5000 if not present
 	u, err := url.Parse(baseURL)
 	if err == nil && u.Port() == "" {
@@ -37,17 +40,21 @@ func NewBrainAdapter(baseURL string) ports.BrainAdapter {
 	}
 	return &BrainAdapter{
 		BaseURL: baseURL,
-		client:  &http.Client{Timeout: 3 * time.Second},
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: metrics.NewTransport(metrics.ComponentBrain, nil),
+		},
 	}
 }
 
-// GetValidatorPubkeys queries /api/v0/brain/validators?format=pubkey and merges all arrays in the response
-func (b *BrainAdapter) GetValidatorPubkeys() ([]string, error) {
+// GetValidatorKeys queries /api/v0/brain/validators?format=pubkey and merges all arrays in the response,
+// tagging each pubkey with the key of its array
+func (b *BrainAdapter) GetValidatorKeys() (domain.ValidatorKeys, error) {
 	endpoint := fmt.Sprintf("%s/api/v0/brain/validators", b.BaseURL)
 
 	u, err := url.Parse(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("invalid brain endpoint: %w", err)
+		return domain.ValidatorKeys{}, fmt.Errorf("invalid brain endpoint: %w", err)
 	}
 	q := u.Query()
 	q.Set("format", "pubkey")
@@ -55,28 +62,32 @@ func (b *BrainAdapter) GetValidatorPubkeys() ([]string, error) {
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating brain request: %w", err)
+		return domain.ValidatorKeys{}, fmt.Errorf("creating brain request: %w", err)
 	}
 
 	resp, err := b.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending brain request: %w", err)
+		return domain.ValidatorKeys{}, fmt.Errorf("error sending brain request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected brain status %d: %s", resp.StatusCode, string(body))
+		return domain.ValidatorKeys{}, fmt.Errorf("unexpected brain status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var result brainValidatorsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding brain response: %w", err)
+		return domain.ValidatorKeys{}, fmt.Errorf("error decoding brain response: %w", err)
 	}
 
-	var pubkeys []string
-	for _, arr := range result {
-		pubkeys = append(pubkeys, arr...)
+	var keys domain.ValidatorKeys
+	for tag, arr := range result {
+		for _, pubkey := range arr {
+			pubkey = domain.NormalizePubkey(pubkey)
+			keys.Pubkeys = append(keys.Pubkeys, pubkey)
+			keys.SetTag(pubkey, tag)
+		}
 	}
-	return pubkeys, nil
+	return keys, nil
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/metrics"
 )
 
 // DappManagerAdapter is the adapter to interact with the DappManager API
@@ -21,7 +22,7 @@ func NewDappManagerAdapter(baseURL string, dnpName string) *DappManagerAdapter {
 	return &DappManagerAdapter{
 		baseURL:       baseURL,
 		signerDnpName: dnpName,
-		client:        &http.Client{},
+		client:        &http.Client{Transport: metrics.NewTransport(metrics.ComponentDappmanager, nil)},
 	}
 }
 
@@ -58,6 +59,24 @@ func (d *DappManagerAdapter) GetNotificationsEnabled(ctx context.Context) (domai
 		string(domain.Notifications.Liveness): {},
 		string(domain.Notifications.Slashed):  {},
 		string(domain.Notifications.Proposal): {},
+
+		string(domain.Notifications.SyncCommittee):     {},
+		string(domain.Notifications.SyncParticipation): {},
+
+		string(domain.Notifications.BeaconUnavailable): {},
+		string(domain.Notifications.BeaconNotSynced):   {},
+
+		string(domain.Notifications.UpcomingProposal): {},
+
+		string(domain.Notifications.MaintenanceWindow): {},
+
+		string(domain.Notifications.DepositSeen):      {},
+		string(domain.Notifications.ActivationQueued): {},
+		string(domain.Notifications.Activated):        {},
+		string(domain.Notifications.ExitInitiated):    {},
+		string(domain.Notifications.Exited):           {},
+		string(domain.Notifications.Withdrawable):     {},
+		string(domain.Notifications.FullyWithdrawn):   {},
 	}
 
 	notifications := make(domain.ValidatorNotificationsEnabled)
@@ -0,0 +1,129 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/metrics"
+)
+
+// ExecutionAdapter queries the JSON-RPC API of an execution client
+type ExecutionAdapter struct {
+	endpoint string
+	client   *http.Client
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+	ID      int    `json:"id"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type rpcBlock struct {
+	BaseFeePerGas string `json:"baseFeePerGas"`
+}
+
+type rpcReceipt struct {
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+}
+
+func NewExecutionAdapter(endpoint string, timeout time.Duration) ports.ExecutionAdapter {
+	return &ExecutionAdapter{
+		endpoint: endpoint,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: metrics.NewTransport(metrics.ComponentExecution, nil),
+		},
+	}
+}
+
+// GetBlockPriorityFees adds up what every transaction of the block paid above the base fee, which is what the fee recipient earns
+func (e *ExecutionAdapter) GetBlockPriorityFees(ctx context.Context, blockHash string) (*big.Int, error) {
+	var block *rpcBlock
+	if err := e.call(ctx, "eth_getBlockByHash", []any{blockHash, false}, &block); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %s not found", blockHash)
+	}
+	baseFee, err := parseQuantity(block.BaseFeePerGas)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base fee: %w", err)
+	}
+
+	var receipts []rpcReceipt
+	if err := e.call(ctx, "eth_getBlockReceipts", []any{blockHash}, &receipts); err != nil {
+		return nil, err
+	}
+	total := new(big.Int)
+	for _, receipt := range receipts {
+		gasUsed, err := parseQuantity(receipt.GasUsed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gas used: %w", err)
+		}
+		gasPrice, err := parseQuantity(receipt.EffectiveGasPrice)
+		if err != nil {
+			return nil, fmt.Errorf("invalid effective gas price: %w", err)
+		}
+		tip := new(big.Int).Sub(gasPrice, baseFee)
+		total.Add(total, tip.Mul(tip, gasUsed))
+	}
+	return total, nil
+}
+
+func (e *ExecutionAdapter) call(ctx context.Context, method string, params []any, result any) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", Method: method, Params: params, ID: 1})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed with status: %s", method, resp.Status)
+	}
+
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s failed: %s (%d)", method, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// parseQuantity parses a hex encoded JSON-RPC quantity such as 0x1a
+func parseQuantity(quantity string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(quantity, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity %q", quantity)
+	}
+	return value, nil
+}
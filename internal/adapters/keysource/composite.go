@@ -0,0 +1,35 @@
+package keysource
+
+import (
+	"fmt"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+)
+
+// compositeKeySource merges the validators of several sources, a validator listed by more than one is tracked once
+type compositeKeySource struct {
+	sources []Source
+}
+
+// NewCompositeKeySource merges the sources. A single source is returned as is.
+func NewCompositeKeySource(sources ...Source) ports.KeySource {
+	if len(sources) == 1 {
+		return sources[0].Adapter
+	}
+	return &compositeKeySource{sources: sources}
+}
+
+// GetValidatorKeys fails if any source fails. Leaving the validators of a failing source out would make the
+// checker drop their state, losing track of the ones already alerted as offline.
+func (c *compositeKeySource) GetValidatorKeys() (domain.ValidatorKeys, error) {
+	var merged domain.ValidatorKeys
+	for _, source := range c.sources {
+		keys, err := source.Adapter.GetValidatorKeys()
+		if err != nil {
+			return domain.ValidatorKeys{}, fmt.Errorf("%s: %w", source.Name, err)
+		}
+		merged.Merge(keys)
+	}
+	return merged, nil
+}
@@ -0,0 +1,65 @@
+package keysource
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// Source is a key source named for the logs
+type Source struct {
+	Name    string
+	Adapter ports.KeySource
+}
+
+// fallbackKeySource reads the keys from the preferred source and falls back to the next ones when it fails,
+// such as the Web3Signer when the Brain is unreachable
+type fallbackKeySource struct {
+	sources []Source
+
+	mu        sync.Mutex
+	preferred int
+}
+
+// NewFallbackKeySource tries the sources in order, the first one is preferred. A single source is returned as is.
+func NewFallbackKeySource(sources ...Source) ports.KeySource {
+	if len(sources) == 1 {
+		return sources[0].Adapter
+	}
+	return &fallbackKeySource{sources: sources}
+}
+
+// GetValidatorKeys calls the sources starting from the one that last succeeded until one succeeds.
+// A source that succeeds with no keys is trusted, as the signer may just be empty.
+func (f *fallbackKeySource) GetValidatorKeys() (domain.ValidatorKeys, error) {
+	f.mu.Lock()
+	start := f.preferred
+	f.mu.Unlock()
+
+	var errs []error
+	for i := range f.sources {
+		idx := (start + i) % len(f.sources)
+		source := f.sources[idx]
+		keys, err := source.Adapter.GetValidatorKeys()
+		if err == nil {
+			f.prefer(idx)
+			return keys, nil
+		}
+		logger.Debug("Key source %s failed, trying the next one: %v", source.Name, err)
+		errs = append(errs, fmt.Errorf("%s: %w", source.Name, err))
+	}
+	return domain.ValidatorKeys{}, errors.Join(errs...)
+}
+
+func (f *fallbackKeySource) prefer(idx int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.preferred != idx {
+		logger.Info("Switching to key source %s", f.sources[idx].Name)
+		f.preferred = idx
+	}
+}
@@ -0,0 +1,115 @@
+package keysource
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// fileKeySource reads the validators from a file with one pubkey or index per line, or from the keystores of a
+// directory. The path is read again whenever it changes, so validators can be added or removed without a restart.
+type fileKeySource struct {
+	path string
+
+	// Called from the checker and the health check, guarded by mu
+	mu      sync.Mutex
+	modTime time.Time
+	keys    domain.ValidatorKeys
+}
+
+func NewFileKeySource(path string) ports.KeySource {
+	return &fileKeySource{path: path}
+}
+
+// GetValidatorKeys returns the validators of the last read, reading the path again if it was modified since.
+// A directory only changes when keystores are added or removed, which is the only way they are updated.
+func (f *fileKeySource) GetValidatorKeys() (domain.ValidatorKeys, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return domain.ValidatorKeys{}, fmt.Errorf("failed to read validators file: %w", err)
+	}
+	if info.ModTime().Equal(f.modTime) {
+		return f.keys, nil
+	}
+
+	var keys domain.ValidatorKeys
+	if info.IsDir() {
+		keys, err = readKeystores(f.path)
+	} else {
+		keys, err = readValidatorsFile(f.path)
+	}
+	if err != nil {
+		return domain.ValidatorKeys{}, err
+	}
+	logger.Info("Read %d pubkeys and %d indices from %s", len(keys.Pubkeys), len(keys.Indices), f.path)
+	f.modTime = info.ModTime()
+	f.keys = keys
+	return keys, nil
+}
+
+// readValidatorsFile reads one pubkey or index per line, skipping empty lines and # comments
+func readValidatorsFile(path string) (domain.ValidatorKeys, error) {
+	var keys domain.ValidatorKeys
+	file, err := os.Open(path)
+	if err != nil {
+		return keys, fmt.Errorf("failed to read validators file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		if err := keys.Add(entry); err != nil {
+			return keys, fmt.Errorf("invalid validators file %s line %d: %w", path, line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return keys, fmt.Errorf("failed to read validators file: %w", err)
+	}
+	return keys, nil
+}
+
+// keystore is the part of an EIP-2335 keystore needed to track its validator, the encrypted key is never read
+type keystore struct {
+	Pubkey string `json:"pubkey"`
+}
+
+// readKeystores reads the pubkeys of the keystores in a directory. Other JSON files, such as slashing
+// protection exports, have no pubkey and are skipped.
+func readKeystores(dir string) (domain.ValidatorKeys, error) {
+	var keys domain.ValidatorKeys
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return keys, fmt.Errorf("failed to list keystores: %w", err)
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return keys, fmt.Errorf("failed to read keystore: %w", err)
+		}
+		var ks keystore
+		if err := json.Unmarshal(content, &ks); err != nil || ks.Pubkey == "" {
+			logger.Debug("Skipping %s, it is not a keystore", path)
+			continue
+		}
+		if err := keys.Add(ks.Pubkey); err != nil {
+			return keys, fmt.Errorf("invalid keystore %s: %w", path, err)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,19 @@
+package keysource
+
+import (
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+)
+
+// staticKeySource returns the validators listed in the config, for setups without a signer to ask
+type staticKeySource struct {
+	keys domain.ValidatorKeys
+}
+
+func NewStaticKeySource(keys domain.ValidatorKeys) ports.KeySource {
+	return &staticKeySource{keys: keys}
+}
+
+func (s *staticKeySource) GetValidatorKeys() (domain.ValidatorKeys, error) {
+	return s.keys, nil
+}
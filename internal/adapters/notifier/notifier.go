@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math/big"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/metrics"
 )
 
 // TODO: discuss isBanner
@@ -21,24 +27,42 @@ type Notifier struct {
 	Category      Category
 	SignerDnpName string
 	HTTPClient    *http.Client
+	// Tag of the validators the notifications are about, such as lido, shown in their titles if set
+	Tag string
+
+	// Shared with the notifiers returned by ForTag
+	delivery *deliveryStatus
 }
 
-func NewNotifier(baseURL, beaconchaUrl, brainUrl, network, signerDnpName string) *Notifier {
-	category := Category(strings.ToLower(network))
-	if network == "mainnet" {
-		category = Ethereum
-	}
+// deliveryStatus holds the error of the last notification sent
+type deliveryStatus struct {
+	mu  sync.Mutex
+	err error
+}
+
+func NewNotifier(baseURL, beaconchaUrl, brainUrl, network, category, signerDnpName string, timeout time.Duration) *Notifier {
 	return &Notifier{
 		BaseURL:       baseURL,
 		BeaconchaUrl:  beaconchaUrl,
 		BrainUrl:      brainUrl,
 		Network:       network,
-		Category:      category,
+		Category:      Category(category),
 		SignerDnpName: signerDnpName,
-		HTTPClient:    &http.Client{Timeout: 3 * time.Second},
+		HTTPClient: &http.Client{
+			Timeout:   timeout,
+			Transport: metrics.NewTransport(metrics.ComponentNotifier, nil),
+		},
+		delivery: &deliveryStatus{},
 	}
 }
 
+// ForTag returns a copy of the notifier that labels its notifications with the tag
+func (n *Notifier) ForTag(tag string) ports.NotifierPort {
+	tagged := *n
+	tagged.Tag = tag
+	return &tagged
+}
+
 type CallToAction struct {
 	Title string `json:"title"`
 	URL   string `json:"url"`
@@ -82,7 +106,25 @@ type NotificationPayload struct {
 	CallToAction  *CallToAction `json:"callToAction,omitempty"`
 }
 
+// LastDeliveryError returns the error of the last notification sent, nil if it was delivered or none was sent yet
+func (n *Notifier) LastDeliveryError() error {
+	n.delivery.mu.Lock()
+	defer n.delivery.mu.Unlock()
+	return n.delivery.err
+}
+
 func (n *Notifier) sendNotification(payload NotificationPayload) error {
+	if n.Tag != "" {
+		payload.Title = fmt.Sprintf("[%s] %s", n.Tag, payload.Title)
+	}
+	err := n.postNotification(payload)
+	n.delivery.mu.Lock()
+	n.delivery.err = err
+	n.delivery.mu.Unlock()
+	return err
+}
+
+func (n *Notifier) postNotification(payload NotificationPayload) error {
 	url := fmt.Sprintf("%s/api/v1/notifications", n.BaseURL)
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -104,15 +146,24 @@ func (n *Notifier) sendNotification(payload NotificationPayload) error {
 	return nil
 }
 
-// SendValidatorLivenessNot sends a notification when one or more validators go offline or online.
-func (n *Notifier) SendValidatorLivenessNot(validators []domain.ValidatorIndex, epoch domain.Epoch, live bool) error {
+// SendValidatorLivenessNot sends a notification for validators going offline or back online, under the
+// correlation ID of their incident so the recovery resolves the notification of the outage. missedRewards is optional.
+func (n *Notifier) SendValidatorLivenessNot(incident domain.ValidatorNotification, validators []domain.ValidatorIndex, epoch domain.Epoch, live bool, missedRewards map[domain.ValidatorIndex]domain.Gwei) error {
+	if len(validators) > 1 {
+		return n.sendValidatorsLivenessNot(incident, validators, epoch, live, missedRewards)
+	}
+	missed, ok := missedRewards[validators[0]]
+	return n.sendValidatorLivenessNot(incident, validators[0], epoch, live, missed, ok)
+}
+
+func (n *Notifier) sendValidatorLivenessNot(incident domain.ValidatorNotification, validator domain.ValidatorIndex, epoch domain.Epoch, live bool, missed domain.Gwei, withRewards bool) error {
 	var title, body string
 	var priority Priority
 	var status Status
 	var isBanner bool
-	correlationId := string(domain.Notifications.Liveness)
+	correlationId := string(incident)
 	var callToAction *CallToAction
-	beaconchaUrl := n.buildBeaconchaURL(validators)
+	beaconchaUrl := n.buildBeaconchaURL([]domain.ValidatorIndex{validator})
 	if beaconchaUrl != "" {
 		callToAction = &CallToAction{
 			Title: "Open in Explorer",
@@ -120,18 +171,63 @@ func (n *Notifier) SendValidatorLivenessNot(validators []domain.ValidatorIndex,
 		}
 	}
 	if live {
-		title = fmt.Sprintf("All validators back online (%d)", len(validators))
-		body = fmt.Sprintf("✅ All validators are back online and atesting at epoch %d on %s (%d).", epoch, n.Network, len(validators))
+		title = fmt.Sprintf("Validator Back Online: %d", validator)
+		body = fmt.Sprintf("✅ Validator %d is back online and attesting at epoch %d on %s.", validator, epoch, n.Network)
 		priority = Low
 		status = Resolved
 		isBanner = false
 	} else {
-		title = fmt.Sprintf("Validator(s) Offline: %s", indexesToString(validators, true))
-		body = fmt.Sprintf("❌ Validator(s) %s are not attesting at epoch %d on %s.", indexesToString(validators, true), epoch, n.Network)
+		title = fmt.Sprintf("Validator Offline: %d", validator)
+		body = fmt.Sprintf("❌ Validator %d is not attesting at epoch %d on %s.", validator, epoch, n.Network)
 		priority = High
 		status = Triggered
 		isBanner = true
 	}
+	if withRewards {
+		body += missedRewardsText(missed)
+	}
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		IsBanner:      &isBanner,
+		CallToAction:  callToAction,
+	}
+	return n.sendNotification(payload)
+}
+
+// sendValidatorsLivenessNot sends a single notification for many validators going offline or back online
+func (n *Notifier) sendValidatorsLivenessNot(incident domain.ValidatorNotification, validators []domain.ValidatorIndex, epoch domain.Epoch, live bool, missedRewards map[domain.ValidatorIndex]domain.Gwei) error {
+	var title, body string
+	var priority Priority
+	var status Status
+	var isBanner bool
+	correlationId := string(incident)
+	var callToAction *CallToAction
+	if beaconchaUrl := n.buildBeaconchaURL(validators); beaconchaUrl != "" {
+		callToAction = &CallToAction{
+			Title: "Open in Explorer",
+			URL:   beaconchaUrl,
+		}
+	}
+	if live {
+		title = fmt.Sprintf("%d Validators Back Online", len(validators))
+		body = fmt.Sprintf("✅ %d validators are back online and attesting at epoch %d on %s: %s.", len(validators), epoch, n.Network, indexesToString(validators, true))
+		priority = Low
+		status = Resolved
+		isBanner = false
+	} else {
+		title = fmt.Sprintf("%d Validators Offline", len(validators))
+		body = fmt.Sprintf("❌ %d validators are not attesting at epoch %d on %s: %s.", len(validators), epoch, n.Network, indexesToString(validators, true))
+		priority = Critical
+		status = Triggered
+		isBanner = true
+	}
+	body += missedRewardsSummary(validators, missedRewards)
 	payload := NotificationPayload{
 		Title:         title,
 		Body:          body,
@@ -173,8 +269,9 @@ func (n *Notifier) SendValidatorsSlashedNot(validators []domain.ValidatorIndex,
 	return n.sendNotification(payload)
 }
 
-// SendBlockProposalNot sends a notification when a block is proposed or missed by one or more validators.
-func (n *Notifier) SendBlockProposalNot(validators []domain.ValidatorIndex, epoch domain.Epoch, proposed bool) error {
+// SendBlockProposalNot sends a notification when a block is proposed, missed or orphaned by one or more validators.
+// missedRewards and the execution layer details of the proposals are optional.
+func (n *Notifier) SendBlockProposalNot(validators []domain.ValidatorIndex, epoch domain.Epoch, outcome domain.ProposalOutcome, missedRewards map[domain.ValidatorIndex]domain.Gwei, proposals []domain.ProposalResult) error {
 	var title, body string
 	var priority Priority
 	var status Status = Triggered
@@ -188,15 +285,59 @@ func (n *Notifier) SendBlockProposalNot(validators []domain.ValidatorIndex, epoc
 			URL:   beaconchaUrl,
 		}
 	}
-	if proposed {
+	switch outcome {
+	case domain.ProposalProposed:
 		title = fmt.Sprintf("Block Proposed: %s", indexesToString(validators, true))
 		body = fmt.Sprintf("✅ Validator(s) %s proposed a block at epoch %d on %s.", indexesToString(validators, true), epoch, n.Network)
 		priority = Low
-	} else {
+		for _, p := range proposals {
+			body += proposalText(p)
+			// A wrong fee recipient means the block value went somewhere else, which needs attention
+			if p.FeeRecipientMismatch {
+				priority = High
+			}
+		}
+	case domain.ProposalOrphaned:
+		title = fmt.Sprintf("Block Orphaned: %s", indexesToString(validators, true))
+		body = fmt.Sprintf("⚠️ Validator(s) %s proposed a block at epoch %d on %s, but it was reorged out of the canonical chain and earned no rewards. This usually means the block was published too late.", indexesToString(validators, true), epoch, n.Network)
+		priority = High
+		body += missedRewardsSummary(validators, missedRewards)
+	default:
 		title = fmt.Sprintf("Block Missed: %s", indexesToString(validators, true))
-		body = fmt.Sprintf("❌ Validator(s) %s missed a block proposal at epoch %d on %s.", indexesToString(validators, true), epoch, n.Network)
+		body = fmt.Sprintf("❌ Validator(s) %s missed a block proposal at epoch %d on %s, no block was produced for their slot.", indexesToString(validators, true), epoch, n.Network)
 		priority = High
+		body += missedRewardsSummary(validators, missedRewards)
+	}
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		IsBanner:      &isBanner,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		CallToAction:  callToAction,
+	}
+	return n.sendNotification(payload)
+}
+
+// SendSyncCommitteeSelectedNot sends a notification when one or more validators are selected for the sync committee.
+func (n *Notifier) SendSyncCommitteeSelectedNot(validators []domain.ValidatorIndex, epoch domain.Epoch) error {
+	title := fmt.Sprintf("Sync Committee Selected: %s", indexesToString(validators, true))
+	body := fmt.Sprintf("📢 Validator(s) %s are part of the sync committee at epoch %d on %s. Keep them online to earn the sync committee rewards.", indexesToString(validators, true), epoch, n.Network)
+	priority := Medium
+	status := Triggered
+	isBanner := false
+	correlationId := string(domain.Notifications.SyncCommittee)
+	var callToAction *CallToAction
+	if beaconchaUrl := n.buildBeaconchaURL(validators); beaconchaUrl != "" {
+		callToAction = &CallToAction{
+			Title: "Open in Explorer",
+			URL:   beaconchaUrl,
+		}
 	}
+
 	payload := NotificationPayload{
 		Title:         title,
 		Body:          body,
@@ -211,6 +352,374 @@ func (n *Notifier) SendBlockProposalNot(validators []domain.ValidatorIndex, epoc
 	return n.sendNotification(payload)
 }
 
+// SendSyncParticipationNot sends a notification when one or more validators included less sync committee signatures than the threshold.
+func (n *Notifier) SendSyncParticipationNot(validators []domain.ValidatorIndex, epoch domain.Epoch, threshold float64) error {
+	title := fmt.Sprintf("Low Sync Committee Participation: %s", indexesToString(validators, true))
+	body := fmt.Sprintf("❌ Validator(s) %s included less than %.0f%% of their sync committee signatures at epoch %d on %s.", indexesToString(validators, true), threshold, epoch, n.Network)
+	priority := High
+	status := Triggered
+	isBanner := true
+	correlationId := string(domain.Notifications.SyncParticipation)
+	var callToAction *CallToAction
+	if beaconchaUrl := n.buildBeaconchaURL(validators); beaconchaUrl != "" {
+		callToAction = &CallToAction{
+			Title: "Open in Explorer",
+			URL:   beaconchaUrl,
+		}
+	}
+
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		IsBanner:      &isBanner,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		CallToAction:  callToAction,
+	}
+	return n.sendNotification(payload)
+}
+
+// SendBeaconUnavailableNot sends a notification when the beacon node has been unreachable for too long, or when it is back.
+func (n *Notifier) SendBeaconUnavailableNot(since time.Time, available bool) error {
+	var title, body string
+	var priority Priority
+	var status Status
+	var isBanner bool
+	correlationId := string(domain.Notifications.BeaconUnavailable)
+	outage := time.Since(since).Round(time.Minute)
+	if available {
+		title = "Beacon node reachable again"
+		body = fmt.Sprintf("✅ The validator tracker can reach the %s beacon node again after %s. Validator duties are being checked.", n.Network, outage)
+		priority = Low
+		status = Resolved
+		isBanner = false
+	} else {
+		title = "Beacon node unavailable"
+		body = fmt.Sprintf("❌ The validator tracker cannot reach the %s beacon node since %s ago. Validator duties are not being checked.", n.Network, outage)
+		priority = High
+		status = Triggered
+		isBanner = true
+	}
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		IsBanner:      &isBanner,
+	}
+	return n.sendNotification(payload)
+}
+
+// SendBeaconNotSyncedNot sends a notification when validator checks are skipped because the beacon node is not synced, or when it is synced again.
+func (n *Notifier) SendBeaconNotSyncedNot(reason string, synced bool) error {
+	var title, body string
+	var priority Priority
+	var status Status
+	var isBanner bool
+	correlationId := string(domain.Notifications.BeaconNotSynced)
+	if synced {
+		title = "Beacon node synced"
+		body = fmt.Sprintf("✅ The %s beacon node is synced and healthy again. Validator duties are being checked.", n.Network)
+		priority = Low
+		status = Resolved
+		isBanner = false
+	} else {
+		title = "Beacon node not synced"
+		body = fmt.Sprintf("⚠️ Validator duties on %s are not being checked because the %s. Results would not be reliable until it recovers.", n.Network, reason)
+		priority = High
+		status = Triggered
+		isBanner = true
+	}
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		IsBanner:      &isBanner,
+	}
+	return n.sendNotification(payload)
+}
+
+// SendUpcomingProposalNot sends a notification ahead of a block proposal, so no maintenance is done meanwhile.
+func (n *Notifier) SendUpcomingProposalNot(validator domain.ValidatorIndex, slot domain.Slot, at time.Time) error {
+	title := fmt.Sprintf("Upcoming Block Proposal: %d", validator)
+	body := fmt.Sprintf("📅 Validator %d will propose a block at slot %d on %s in ~%s. Avoid restarting your clients or doing maintenance until then.", validator, slot, n.Network, time.Until(at).Round(time.Minute))
+	priority := Medium
+	status := Triggered
+	isBanner := false
+	correlationId := string(domain.Notifications.UpcomingProposal)
+	var callToAction *CallToAction
+	if beaconchaUrl := n.buildBeaconchaURL([]domain.ValidatorIndex{validator}); beaconchaUrl != "" {
+		callToAction = &CallToAction{
+			Title: "Open in Explorer",
+			URL:   beaconchaUrl,
+		}
+	}
+
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		IsBanner:      &isBanner,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		CallToAction:  callToAction,
+	}
+	return n.sendNotification(payload)
+}
+
+// SendMaintenanceEndedNot sends a summary of the notifications silenced during a maintenance window once it ends.
+func (n *Notifier) SendMaintenanceEndedNot(silence domain.Silence) error {
+	title := "Maintenance window ended"
+	window := silence.StartsAt.UTC().Format(time.RFC822)
+	if silence.Reason != "" {
+		window += fmt.Sprintf(" (%s)", silence.Reason)
+	}
+	body := fmt.Sprintf("🔔 The maintenance window on %s started %s has ended, notifications are sent again.", n.Network, window)
+	priority := Low
+	status := Triggered
+	isBanner := false
+	correlationId := string(domain.Notifications.MaintenanceWindow)
+	var silenced []domain.ValidatorIndex
+	if len(silence.Suppressed) == 0 {
+		body += " No notification was silenced."
+	} else {
+		// Silenced alerts may need attention, as they are only sent again if the problem persists
+		priority = Medium
+		body += " Silenced during the window:" + suppressedText(silence.Suppressed, n.Network)
+		for _, validators := range silence.Suppressed {
+			silenced = append(silenced, validators...)
+		}
+		slices.Sort(silenced)
+		silenced = slices.Compact(silenced)
+	}
+	var callToAction *CallToAction
+	if beaconchaUrl := n.buildBeaconchaURL(silenced); beaconchaUrl != "" {
+		callToAction = &CallToAction{
+			Title: "Open in Explorer",
+			URL:   beaconchaUrl,
+		}
+	}
+
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		IsBanner:      &isBanner,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		CallToAction:  callToAction,
+	}
+	return n.sendNotification(payload)
+}
+
+// SendValidatorLifecycleNot sends a notification when one or more validators reach a status of their lifecycle,
+// from their deposit to their full withdrawal. Validators waiting for activation are listed with its estimate.
+func (n *Notifier) SendValidatorLifecycleNot(lifecycleStatus domain.LifecycleStatus, validators []domain.ValidatorLifecycle) error {
+	names := lifecycleNames(validators)
+	var title, body string
+	priority := Low
+	var details []string
+	switch lifecycleStatus {
+	case domain.LifecycleDeposited:
+		title = fmt.Sprintf("Validator Deposit Seen: %s", names)
+		body = fmt.Sprintf("📥 The deposit of validator(s) %s is waiting in the pending deposits queue on %s.", names, n.Network)
+		details = activationDetails(validators)
+	case domain.LifecyclePendingInitialized:
+		title = fmt.Sprintf("Validator Deposit Processed: %s", names)
+		body = fmt.Sprintf("📥 The deposit of validator(s) %s was processed on %s, they will join the activation queue.", names, n.Network)
+		details = activationDetails(validators)
+	case domain.LifecyclePendingQueued:
+		title = fmt.Sprintf("Validator(s) in Activation Queue: %s", names)
+		body = fmt.Sprintf("⏳ Validator(s) %s are waiting for activation on %s. Make sure they are running before they activate.", names, n.Network)
+		details = activationDetails(validators)
+	case domain.LifecycleActiveOngoing:
+		title = fmt.Sprintf("Validator(s) Activated: %s", names)
+		body = fmt.Sprintf("✅ Validator(s) %s are active on %s and started performing their duties.", names, n.Network)
+	case domain.LifecycleActiveExiting:
+		title = fmt.Sprintf("Validator(s) Exiting: %s", names)
+		body = fmt.Sprintf("🚪 Validator(s) %s initiated their exit on %s. Keep them online until they exit to avoid penalties.", names, n.Network)
+		priority = Medium
+		for _, v := range validators {
+			if v.Validator != nil {
+				details = append(details, fmt.Sprintf("%d exits at epoch %d", v.Validator.Index, v.Validator.ExitEpoch))
+			}
+		}
+	case domain.LifecycleExitedUnslashed, domain.LifecycleExitedSlashed:
+		title = fmt.Sprintf("Validator(s) Exited: %s", names)
+		body = fmt.Sprintf("👋 Validator(s) %s exited on %s and no longer have duties.", names, n.Network)
+		for _, v := range validators {
+			if v.Validator != nil {
+				details = append(details, fmt.Sprintf("%d is withdrawable at epoch %d", v.Validator.Index, v.Validator.WithdrawableEpoch))
+			}
+		}
+	case domain.LifecycleWithdrawalPossible:
+		title = fmt.Sprintf("Validator(s) Withdrawable: %s", names)
+		body = fmt.Sprintf("💸 The balance of validator(s) %s is withdrawable on %s and will be sent to their withdrawal address.", names, n.Network)
+	case domain.LifecycleWithdrawalDone:
+		title = fmt.Sprintf("Validator(s) Fully Withdrawn: %s", names)
+		body = fmt.Sprintf("🏁 Validator(s) %s were fully withdrawn on %s. Their keys can be removed.", names, n.Network)
+	default:
+		return fmt.Errorf("no notification for validator status %s", lifecycleStatus)
+	}
+	if len(details) > 0 {
+		body += " " + strings.Join(details, ", ") + "."
+	}
+	status := Triggered
+	isBanner := false
+	notification, _ := domain.Notifications.ForLifecycle(lifecycleStatus)
+	correlationId := string(notification)
+
+	var indexes []domain.ValidatorIndex
+	for _, v := range validators {
+		if v.Validator != nil {
+			indexes = append(indexes, v.Validator.Index)
+		}
+	}
+	var callToAction *CallToAction
+	if lifecycleStatus == domain.LifecycleWithdrawalDone {
+		callToAction = &CallToAction{
+			Title: "Remove validators",
+			URL:   n.BrainUrl,
+		}
+	} else if beaconchaUrl := n.buildBeaconchaURL(indexes); beaconchaUrl != "" {
+		callToAction = &CallToAction{
+			Title: "Open in Explorer",
+			URL:   beaconchaUrl,
+		}
+	}
+
+	payload := NotificationPayload{
+		Title:         title,
+		Body:          body,
+		Category:      &n.Category,
+		Priority:      &priority,
+		IsBanner:      &isBanner,
+		DnpName:       &n.SignerDnpName,
+		Status:        &status,
+		CorrelationId: &correlationId,
+		CallToAction:  callToAction,
+	}
+	return n.sendNotification(payload)
+}
+
+// Helper to name validators by index, or by shortened pubkey while they have none
+func lifecycleNames(validators []domain.ValidatorLifecycle) string {
+	var s []string
+	max := 10
+	for i, v := range validators {
+		if i == max {
+			s = append(s, "...")
+			break
+		}
+		s = append(s, lifecycleName(v))
+	}
+	return strings.Join(s, ",")
+}
+
+func lifecycleName(v domain.ValidatorLifecycle) string {
+	if v.Validator != nil {
+		return fmt.Sprintf("%d", v.Validator.Index)
+	}
+	if len(v.Pubkey) > 14 {
+		return v.Pubkey[:10] + "…" + v.Pubkey[len(v.Pubkey)-4:]
+	}
+	return v.Pubkey
+}
+
+// Helper to describe the queue position and the expected activation of each pending validator
+func activationDetails(validators []domain.ValidatorLifecycle) []string {
+	var details []string
+	for _, v := range validators {
+		a := v.Activation
+		if a == nil {
+			continue
+		}
+		var parts []string
+		if a.QueuePosition > 0 {
+			parts = append(parts, fmt.Sprintf("position %d of %d in the deposits queue", a.QueuePosition, a.QueueLength))
+		}
+		if a.Epoch > 0 {
+			when := "expected"
+			if a.Scheduled {
+				when = "scheduled"
+			}
+			activation := fmt.Sprintf("activation %s at epoch %d", when, a.Epoch)
+			if !a.Time.IsZero() {
+				activation += fmt.Sprintf(" (%s)", a.Time.UTC().Format(time.RFC822))
+			}
+			parts = append(parts, activation)
+		}
+		if len(parts) > 0 {
+			details = append(details, fmt.Sprintf("%s: %s", lifecycleName(v), strings.Join(parts, ", ")))
+		}
+	}
+	return details
+}
+
+// Helper to list the notifications silenced during a maintenance window, by name without the network prefix
+func suppressedText(suppressed map[domain.ValidatorNotification][]domain.ValidatorIndex, network string) string {
+	notifications := slices.Sorted(maps.Keys(suppressed))
+	var lines []string
+	for _, notification := range notifications {
+		line := strings.TrimPrefix(string(notification), network+"-")
+		if validators := suppressed[notification]; len(validators) > 0 {
+			line += fmt.Sprintf(" for validator(s) %s", indexesToString(validators, true))
+		}
+		lines = append(lines, line)
+	}
+	return " " + strings.Join(lines, "; ") + "."
+}
+
+// Helper to describe the rewards missed during the day
+func missedRewardsText(missed domain.Gwei) string {
+	return fmt.Sprintf(" Missed rewards today: %.6f ETH (%d gwei).", missed.ETH(), missed)
+}
+
+// Helper to describe the rewards several validators missed during the day, empty if they are unknown
+func missedRewardsSummary(validators []domain.ValidatorIndex, missedRewards map[domain.ValidatorIndex]domain.Gwei) string {
+	if missedRewards == nil {
+		return ""
+	}
+	var missed domain.Gwei
+	for _, validator := range validators {
+		missed += missedRewards[validator]
+	}
+	return missedRewardsText(missed)
+}
+
+// Helper to describe the execution layer details of a proposal
+func proposalText(p domain.ProposalResult) string {
+	text := fmt.Sprintf(" Slot %d (block %d)", p.Slot, p.BlockNumber)
+	if p.BlockValue != nil {
+		value, _ := new(big.Float).Quo(new(big.Float).SetInt(p.BlockValue), big.NewFloat(1e18)).Float64()
+		text += fmt.Sprintf(": %.6f ETH", value)
+	}
+	if p.IsMev() {
+		text += fmt.Sprintf(", delivered by MEV relay %s", p.MevRelay)
+	} else {
+		text += ", built locally"
+	}
+	text += fmt.Sprintf(", fee recipient %s.", p.FeeRecipient)
+	if p.FeeRecipientMismatch {
+		text += fmt.Sprintf(" ⚠️ The configured fee recipient is %s!", p.ExpectedFeeRecipient)
+	}
+	return text
+}
+
 // Helper to join validator indexes as comma-separated string
 // If truncate is true, only the first 10 are shown, then '...'.
 func indexesToString(indexes []domain.ValidatorIndex, truncate bool) string {
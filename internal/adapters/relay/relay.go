@@ -0,0 +1,98 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/metrics"
+)
+
+// RelayAdapter queries the data API of every configured MEV relay, see
+// https://flashbots.github.io/relay-specs/#/Data/getDeliveredPayloads
+type RelayAdapter struct {
+	relays []string
+	client *http.Client
+}
+
+type bidTrace struct {
+	Slot                 string `json:"slot"`
+	BlockHash            string `json:"block_hash"`
+	BuilderPubkey        string `json:"builder_pubkey"`
+	ProposerFeeRecipient string `json:"proposer_fee_recipient"`
+	Value                string `json:"value"`
+}
+
+func NewRelayAdapter(relays []string, timeout time.Duration) ports.RelayAdapter {
+	urls := make([]string, len(relays))
+	for i, relay := range relays {
+		urls[i] = strings.TrimSuffix(relay, "/")
+	}
+	return &RelayAdapter{
+		relays: urls,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: metrics.NewTransport(metrics.ComponentRelay, nil),
+		},
+	}
+}
+
+// GetDeliveredPayloads asks every relay for the payload it delivered at a slot. Relays that fail are skipped,
+// an error is only returned if none answered.
+func (r *RelayAdapter) GetDeliveredPayloads(ctx context.Context, slot domain.Slot) ([]domain.RelayPayload, error) {
+	var payloads []domain.RelayPayload
+	var errs []error
+	for _, relay := range r.relays {
+		traces, err := r.getDeliveredPayloads(ctx, relay, slot)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("relay %s: %w", relay, err))
+			continue
+		}
+		for _, trace := range traces {
+			value, ok := new(big.Int).SetString(trace.Value, 10)
+			if !ok {
+				value = nil
+			}
+			payloads = append(payloads, domain.RelayPayload{
+				Relay:                relay,
+				BlockHash:            trace.BlockHash,
+				BuilderPubkey:        trace.BuilderPubkey,
+				ProposerFeeRecipient: trace.ProposerFeeRecipient,
+				Value:                value,
+			})
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(r.relays) {
+		return nil, errors.Join(errs...)
+	}
+	return payloads, nil
+}
+
+func (r *RelayAdapter) getDeliveredPayloads(ctx context.Context, relay string, slot domain.Slot) ([]bidTrace, error) {
+	url := fmt.Sprintf("%s/relay/v1/data/bidtraces/proposer_payload_delivered?slot=%d", relay, slot)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch delivered payloads: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var traces []bidTrace
+	if err := json.NewDecoder(resp.Body).Decode(&traces); err != nil {
+		return nil, fmt.Errorf("failed to decode delivered payloads: %w", err)
+	}
+	return traces, nil
+}
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+)
+
+// maxEpochResults is the number of epoch results kept on disk, roughly one week on mainnet
+const maxEpochResults = 1575
+
+// fullEpochResults is the number of recent epoch results kept with every per-validator result, roughly one day
+// on mainnet. Older ones are compacted to their incidents, so the history does not grow with the validators.
+const fullEpochResults = 225
+
+// FileStore persists the state as JSON files next to the state file, meant to live in a docker volume.
+// The checker state, the maintenance windows, the validator indices and each epoch result have their own
+// file, so a save only writes what changed. Every write replaces its file atomically so a crash never
+// leaves it half written.
+type FileStore struct {
+	// Checker state, the other files are named after it
+	path         string
+	silencesPath string
+	indicesPath  string
+	epochsDir    string
+	// Serializes the writes, which share their temporary file names
+	mu sync.Mutex
+}
+
+// checkerFile is the content of the state file. The other fields are only read to migrate the single
+// file written by previous versions.
+type checkerFile struct {
+	Checker          *domain.CheckerState                `json:"checker,omitempty"`
+	Epochs           map[domain.Epoch]domain.EpochResult `json:"epochs,omitempty"`
+	Silences         []domain.Silence                    `json:"silences,omitempty"`
+	ValidatorIndices map[string]domain.ValidatorIndex    `json:"validatorIndices,omitempty"`
+}
+
+// NewFileStore creates the store, migrating the single state file of previous versions if found
+func NewFileStore(path string) (ports.StateStore, error) {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	s := &FileStore{
+		path:         path,
+		silencesPath: base + "-silences.json",
+		indicesPath:  base + "-indices.json",
+		epochsDir:    base + "-epochs",
+	}
+	if err := os.MkdirAll(s.epochsDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	var file checkerFile
+	found, err := readFile(path, &file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+	if found && (file.Epochs != nil || file.Silences != nil || file.ValidatorIndices != nil) {
+		if err := s.migrate(file); err != nil {
+			return nil, fmt.Errorf("failed to migrate state file %s: %w", path, err)
+		}
+	}
+	if err := s.pruneEpochResults(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate splits the single state file of previous versions into the files of each kind of state
+func (s *FileStore) migrate(file checkerFile) error {
+	// In order, so the retention applies to the older results as the newer ones are saved
+	for _, epoch := range slices.Sorted(maps.Keys(file.Epochs)) {
+		if err := s.SaveEpochResult(file.Epochs[epoch]); err != nil {
+			return err
+		}
+	}
+	if file.Silences != nil {
+		if err := s.SaveSilences(file.Silences); err != nil {
+			return err
+		}
+	}
+	if file.ValidatorIndices != nil {
+		if err := s.SaveValidatorIndices(file.ValidatorIndices); err != nil {
+			return err
+		}
+	}
+	if file.Checker != nil {
+		return s.SaveCheckerState(*file.Checker)
+	}
+	return os.Remove(s.path)
+}
+
+// LoadCheckerState returns the persisted checker state. The boolean is false if nothing was persisted yet.
+func (s *FileStore) LoadCheckerState() (domain.CheckerState, bool, error) {
+	var file checkerFile
+	found, err := readFile(s.path, &file)
+	if err != nil || file.Checker == nil {
+		return domain.CheckerState{}, false, err
+	}
+	return *file.Checker, found, nil
+}
+
+func (s *FileStore) SaveCheckerState(state domain.CheckerState) error {
+	return s.writeFile(s.path, checkerFile{Checker: &state})
+}
+
+// SaveEpochResult stores the result of an epoch, compacting the ones older than fullEpochResults and
+// dropping the ones older than maxEpochResults
+func (s *FileStore) SaveEpochResult(result domain.EpochResult) error {
+	if err := s.writeFile(s.epochPath(result.Epoch), result); err != nil {
+		return err
+	}
+	if result.Epoch >= fullEpochResults {
+		old, found, err := s.GetEpochResult(result.Epoch - fullEpochResults)
+		if err != nil {
+			return err
+		}
+		if found && !old.Compacted {
+			if err := s.writeFile(s.epochPath(old.Epoch), old.Compact()); err != nil {
+				return err
+			}
+		}
+	}
+	if result.Epoch >= maxEpochResults {
+		if err := os.Remove(s.epochPath(result.Epoch - maxEpochResults)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to drop epoch result: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) GetEpochResult(epoch domain.Epoch) (domain.EpochResult, bool, error) {
+	var result domain.EpochResult
+	found, err := readFile(s.epochPath(epoch), &result)
+	return result, found, err
+}
+
+func (s *FileStore) LoadSilences() ([]domain.Silence, error) {
+	var silences []domain.Silence
+	_, err := readFile(s.silencesPath, &silences)
+	return silences, err
+}
+
+func (s *FileStore) SaveSilences(silences []domain.Silence) error {
+	return s.writeFile(s.silencesPath, silences)
+}
+
+func (s *FileStore) LoadValidatorIndices() (map[string]domain.ValidatorIndex, error) {
+	var indices map[string]domain.ValidatorIndex
+	_, err := readFile(s.indicesPath, &indices)
+	return indices, err
+}
+
+func (s *FileStore) SaveValidatorIndices(indices map[string]domain.ValidatorIndex) error {
+	return s.writeFile(s.indicesPath, indices)
+}
+
+// pruneEpochResults applies the retention to the epoch results left by a previous run, which may have been
+// stopped for longer than the retention or skipped the epochs whose save compacts the older ones
+func (s *FileStore) pruneEpochResults() error {
+	entries, err := os.ReadDir(s.epochsDir)
+	if err != nil {
+		return fmt.Errorf("failed to list epoch results: %w", err)
+	}
+	var epochs []domain.Epoch
+	var latest domain.Epoch
+	for _, entry := range entries {
+		epoch, err := strconv.ParseUint(strings.TrimSuffix(entry.Name(), ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+		epochs = append(epochs, domain.Epoch(epoch))
+		latest = max(latest, domain.Epoch(epoch))
+	}
+	for _, epoch := range epochs {
+		switch {
+		case latest-epoch >= maxEpochResults:
+			if err := os.Remove(s.epochPath(epoch)); err != nil {
+				return fmt.Errorf("failed to drop epoch result: %w", err)
+			}
+		case latest-epoch >= fullEpochResults:
+			result, _, err := s.GetEpochResult(epoch)
+			if err != nil {
+				return err
+			}
+			if !result.Compacted {
+				if err := s.writeFile(s.epochPath(epoch), result.Compact()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) epochPath(epoch domain.Epoch) string {
+	return filepath.Join(s.epochsDir, fmt.Sprintf("%d.json", epoch))
+}
+
+// writeFile encodes the value, writes it to a temporary file and renames it over the file
+func (s *FileStore) writeFile(path string, value any) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+// readFile decodes a file into value. The boolean is false if the file does not exist.
+func readFile(path string, value any) (bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(content, value); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return true, nil
+}
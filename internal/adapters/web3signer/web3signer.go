@@ -0,0 +1,61 @@
+package web3signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/metrics"
+)
+
+// Web3Signer rejects requests from hosts that are not in its allowlist, so this adapter only works where the
+// tracker host is whitelisted. Otherwise the Brain adapter must be used.
+
+type Web3SignerAdapter struct {
+	BaseURL string
+	client  *http.Client
+}
+
+func NewWeb3SignerAdapter(baseURL string, timeout time.Duration) ports.KeySource {
+	return &Web3SignerAdapter{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: metrics.NewTransport(metrics.ComponentWeb3Signer, nil),
+		},
+	}
+}
+
+// GetValidatorKeys queries /api/v1/eth2/publicKeys, which lists the keys loaded in the signer
+func (w *Web3SignerAdapter) GetValidatorKeys() (domain.ValidatorKeys, error) {
+	req, err := http.NewRequest("GET", w.BaseURL+"/api/v1/eth2/publicKeys", nil)
+	if err != nil {
+		return domain.ValidatorKeys{}, fmt.Errorf("creating web3signer request: %w", err)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return domain.ValidatorKeys{}, fmt.Errorf("error sending web3signer request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return domain.ValidatorKeys{}, fmt.Errorf("unexpected web3signer status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var pubkeys []string
+	if err := json.NewDecoder(resp.Body).Decode(&pubkeys); err != nil {
+		return domain.ValidatorKeys{}, fmt.Errorf("error decoding web3signer response: %w", err)
+	}
+	var keys domain.ValidatorKeys
+	for _, pubkey := range pubkeys {
+		keys.Pubkeys = append(keys.Pubkeys, domain.NormalizePubkey(pubkey))
+	}
+	return keys, nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+type ChainEventType string
+
+const (
+	ChainEventHead                ChainEventType = "head"
+	ChainEventBlock               ChainEventType = "block"
+	ChainEventFinalizedCheckpoint ChainEventType = "finalized_checkpoint"
+	ChainEventReorg               ChainEventType = "chain_reorg"
+)
+
+// ChainEvent is an event streamed by the beacon node that may require new checks
+type ChainEvent struct {
+	Type  ChainEventType
+	Slot  Slot
+	Epoch Epoch
+	// Only set for head events, true for the first block of an epoch
+	EpochTransition bool
+	// Only set for reorg events
+	Depth uint64
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// SyncStatus is the sync state reported by the beacon node
+type SyncStatus struct {
+	HeadSlot     Slot `json:"headSlot"`
+	SyncDistance Slot `json:"syncDistance"`
+	IsSyncing    bool `json:"isSyncing"`
+	IsOptimistic bool `json:"isOptimistic"`
+	ElOffline    bool `json:"elOffline"`
+}
+
+// NodeHealth is the health reported by the beacon node /eth/v1/node/health endpoint
+type NodeHealth string
+
+const (
+	NodeHealthReady          NodeHealth = "ready"
+	NodeHealthSyncing        NodeHealth = "syncing"
+	NodeHealthNotInitialized NodeHealth = "not_initialized"
+)
+
+// DependencyHealth is the status of one of the services the tracker relies on
+type DependencyHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// LivenessReport is served by the health endpoint. Live is false when the checker is stuck. It is built from
+// the state of the tracker alone, so probing it never reaches the dependencies.
+type LivenessReport struct {
+	Live                bool      `json:"live"`
+	LastCheckAt         time.Time `json:"lastCheckAt"`
+	LastCheckAgeSeconds float64   `json:"lastCheckAgeSeconds"`
+	LastCheckSucceeded  bool      `json:"lastCheckSucceeded"`
+}
+
+// HealthReport is served by the readiness endpoint. Ready is false when the tracker cannot currently judge validators.
+type HealthReport struct {
+	LivenessReport
+	Ready        bool               `json:"ready"`
+	Dependencies []DependencyHealth `json:"dependencies"`
+	BeaconSync   *SyncStatus        `json:"beaconSync,omitempty"`
+}
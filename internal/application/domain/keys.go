@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var pubkeyRegex = regexp.MustCompile(`^0x[0-9a-f]{96}$`)
+
+// ValidatorKeys are the validators to track, given by pubkey or, for setups that know them, directly by index
+type ValidatorKeys struct {
+	Pubkeys []string
+	Indices []ValidatorIndex
+	// Staking protocol of the pubkeys, such as solo or lido, as the Brain groups them. Untagged pubkeys are not listed.
+	Tags map[string]string
+}
+
+// Empty returns true if there is no validator to track
+func (k ValidatorKeys) Empty() bool {
+	return len(k.Pubkeys) == 0 && len(k.Indices) == 0
+}
+
+// Add parses a validator given either as a hex pubkey, with or without 0x prefix, or as a decimal index
+func (k *ValidatorKeys) Add(entry string) error {
+	entry = strings.TrimSpace(entry)
+	if index, err := strconv.ParseUint(entry, 10, 64); err == nil {
+		k.Indices = append(k.Indices, ValidatorIndex(index))
+		return nil
+	}
+	pubkey := NormalizePubkey(entry)
+	if !pubkeyRegex.MatchString(pubkey) {
+		return fmt.Errorf("%s is neither a validator pubkey nor an index", entry)
+	}
+	k.Pubkeys = append(k.Pubkeys, pubkey)
+	return nil
+}
+
+// Merge adds the validators of other that are not already in k. A pubkey keeps the tag it already had.
+func (k *ValidatorKeys) Merge(other ValidatorKeys) {
+	pubkeys := make(map[string]bool, len(k.Pubkeys))
+	for _, pubkey := range k.Pubkeys {
+		pubkeys[NormalizePubkey(pubkey)] = true
+	}
+	for _, pubkey := range other.Pubkeys {
+		tag := other.Tags[pubkey]
+		if pubkey = NormalizePubkey(pubkey); !pubkeys[pubkey] {
+			pubkeys[pubkey] = true
+			k.Pubkeys = append(k.Pubkeys, pubkey)
+		}
+		if _, tagged := k.Tags[pubkey]; tag != "" && !tagged {
+			k.SetTag(pubkey, tag)
+		}
+	}
+	indices := make(map[ValidatorIndex]bool, len(k.Indices))
+	for _, index := range k.Indices {
+		indices[index] = true
+	}
+	for _, index := range other.Indices {
+		if !indices[index] {
+			indices[index] = true
+			k.Indices = append(k.Indices, index)
+		}
+	}
+}
+
+// SetTag tags a pubkey, which must be normalized
+func (k *ValidatorKeys) SetTag(pubkey, tag string) {
+	if k.Tags == nil {
+		k.Tags = make(map[string]string)
+	}
+	k.Tags[pubkey] = tag
+}
+
+// NormalizePubkey lowercases a hex pubkey and adds the 0x prefix, so the same key is always spelled the same
+func NormalizePubkey(pubkey string) string {
+	pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+	if !strings.HasPrefix(pubkey, "0x") {
+		pubkey = "0x" + pubkey
+	}
+	return pubkey
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// LifecycleStatus is the status of a validator as named by the beacon API, such as active_ongoing
+type LifecycleStatus string
+
+const (
+	// LifecycleDeposited is not a beacon API status: the deposit waits in the pending deposits queue and
+	// the validator has no index yet
+	LifecycleDeposited          LifecycleStatus = "deposited"
+	LifecyclePendingInitialized LifecycleStatus = "pending_initialized"
+	LifecyclePendingQueued      LifecycleStatus = "pending_queued"
+	LifecycleActiveOngoing      LifecycleStatus = "active_ongoing"
+	LifecycleActiveExiting      LifecycleStatus = "active_exiting"
+	LifecycleActiveSlashed      LifecycleStatus = "active_slashed"
+	LifecycleExitedUnslashed    LifecycleStatus = "exited_unslashed"
+	LifecycleExitedSlashed      LifecycleStatus = "exited_slashed"
+	LifecycleWithdrawalPossible LifecycleStatus = "withdrawal_possible"
+	LifecycleWithdrawalDone     LifecycleStatus = "withdrawal_done"
+)
+
+// FarFutureEpoch is the epoch of the transitions that are not scheduled yet
+const FarFutureEpoch = Epoch(math.MaxUint64)
+
+// Active returns true if the validator has duties, including while exiting or slashed
+func (s LifecycleStatus) Active() bool {
+	return s == LifecycleActiveOngoing || s == LifecycleActiveExiting || s == LifecycleActiveSlashed
+}
+
+// Pending returns true if the validator waits for activation
+func (s LifecycleStatus) Pending() bool {
+	return s == LifecycleDeposited || s == LifecyclePendingInitialized || s == LifecyclePendingQueued
+}
+
+// ValidatorState is a validator of the beacon chain registry
+type ValidatorState struct {
+	Index   ValidatorIndex  `json:"index"`
+	Status  LifecycleStatus `json:"status"`
+	Balance Gwei            `json:"balance"`
+	Slashed bool            `json:"slashed"`
+	// FarFutureEpoch until scheduled
+	ActivationEligibilityEpoch Epoch `json:"activationEligibilityEpoch"`
+	ActivationEpoch            Epoch `json:"activationEpoch"`
+	ExitEpoch                  Epoch `json:"exitEpoch"`
+	WithdrawableEpoch          Epoch `json:"withdrawableEpoch"`
+}
+
+// NextTransition returns the epoch the status is scheduled to change at, false if no change is scheduled
+func (s ValidatorState) NextTransition() (Epoch, bool) {
+	var next Epoch
+	switch s.Status {
+	case LifecyclePendingQueued:
+		next = s.ActivationEpoch
+	case LifecycleActiveExiting, LifecycleActiveSlashed:
+		next = s.ExitEpoch
+	case LifecycleExitedUnslashed, LifecycleExitedSlashed:
+		next = s.WithdrawableEpoch
+	default:
+		return 0, false
+	}
+	return next, next != FarFutureEpoch
+}
+
+// PendingDeposit is a deposit waiting in the pending deposits queue of the beacon chain
+type PendingDeposit struct {
+	Pubkey string `json:"pubkey"`
+	Amount Gwei   `json:"amount"`
+}
+
+// ActivationEstimate is when a pending validator is expected to be activated
+type ActivationEstimate struct {
+	// Position in the pending deposits queue, starting at 1, only while the deposit waits in it
+	QueuePosition uint64 `json:"queuePosition,omitempty"`
+	QueueLength   uint64 `json:"queueLength,omitempty"`
+	// Exact once the beacon chain schedules the activation, estimated from the churn limit until then.
+	// Unset for a queued deposit if the chain spec of the beacon node could not be read.
+	Epoch     Epoch     `json:"epoch,omitempty"`
+	Time      time.Time `json:"time,omitzero"`
+	Scheduled bool      `json:"scheduled"`
+}
+
+// ValidatorLifecycle is where a tracked validator is between its deposit and its full withdrawal
+type ValidatorLifecycle struct {
+	Pubkey string          `json:"pubkey"`
+	Status LifecycleStatus `json:"status"`
+	// Nil while the deposit waits in the pending deposits queue
+	Validator *ValidatorState `json:"validator,omitempty"`
+	// Only set while the validator waits for activation, nil if it could not be estimated
+	Activation *ActivationEstimate `json:"activation,omitempty"`
+}
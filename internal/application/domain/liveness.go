@@ -0,0 +1,32 @@
+package domain
+
+// LivenessState tracks the consecutive liveness results of a validator, so a single missed
+// epoch does not raise an alert and a single live epoch does not resolve it
+type LivenessState struct {
+	ConsecutiveMissed uint64 `json:"consecutiveMissed"`
+	ConsecutiveLive   uint64 `json:"consecutiveLive"`
+	Offline           bool   `json:"offline"`
+}
+
+// Update records the liveness of an epoch and returns true if the validator switched between online and offline.
+// A validator goes offline after missedToAlert consecutive missed epochs and back online after liveToResolve
+// consecutive live epochs.
+func (s *LivenessState) Update(live bool, missedToAlert, liveToResolve uint64) bool {
+	if live {
+		s.ConsecutiveLive++
+		s.ConsecutiveMissed = 0
+		if s.Offline && s.ConsecutiveLive >= liveToResolve {
+			s.Offline = false
+			return true
+		}
+		return false
+	}
+
+	s.ConsecutiveMissed++
+	s.ConsecutiveLive = 0
+	if !s.Offline && s.ConsecutiveMissed >= missedToAlert {
+		s.Offline = true
+		return true
+	}
+	return false
+}
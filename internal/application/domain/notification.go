@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"fmt"
+	"slices"
+)
+
 type ValidatorNotificationsEnabled map[ValidatorNotification]bool
 
 type ValidatorNotification string
@@ -8,14 +13,117 @@ type validatorNotifications struct {
 	Liveness ValidatorNotification
 	Slashed  ValidatorNotification
 	Proposal ValidatorNotification
+
+	SyncCommittee     ValidatorNotification
+	SyncParticipation ValidatorNotification
+
+	BeaconUnavailable ValidatorNotification
+	BeaconNotSynced   ValidatorNotification
+
+	UpcomingProposal ValidatorNotification
+
+	MaintenanceWindow ValidatorNotification
+
+	// Lifecycle of the validators, from their deposit to their full withdrawal
+	DepositSeen      ValidatorNotification
+	ActivationQueued ValidatorNotification
+	Activated        ValidatorNotification
+	ExitInitiated    ValidatorNotification
+	Exited           ValidatorNotification
+	Withdrawable     ValidatorNotification
+	FullyWithdrawn   ValidatorNotification
+
+	network string
 }
 
 var Notifications validatorNotifications
 
+// ForValidator returns the correlation ID of the incident of a single validator, so the notifier can
+// resolve each validator separately. The notification is still enabled or disabled by the base ID.
+func (n ValidatorNotification) ForValidator(index ValidatorIndex) ValidatorNotification {
+	return ValidatorNotification(fmt.Sprintf("%s-%d", n, index))
+}
+
+// ForGroup returns the correlation ID of the incident of several validators notified together at an epoch,
+// named after the first one so the groups of different tags do not collide
+func (n ValidatorNotification) ForGroup(epoch Epoch, first ValidatorIndex) ValidatorNotification {
+	return ValidatorNotification(fmt.Sprintf("%s-%d-epoch-%d", n, first, epoch))
+}
+
+// All returns the correlation IDs of every notification
+func (n validatorNotifications) All() []ValidatorNotification {
+	return []ValidatorNotification{
+		n.Liveness, n.Slashed, n.Proposal,
+		n.SyncCommittee, n.SyncParticipation,
+		n.BeaconUnavailable, n.BeaconNotSynced,
+		n.UpcomingProposal,
+		n.MaintenanceWindow,
+		n.DepositSeen, n.ActivationQueued, n.Activated, n.ExitInitiated, n.Exited, n.Withdrawable, n.FullyWithdrawn,
+	}
+}
+
+// ForLifecycle returns the correlation ID of the notification sent when a validator reaches a status. The
+// boolean is false for active_slashed, which the slashing notification covers.
+func (n validatorNotifications) ForLifecycle(status LifecycleStatus) (ValidatorNotification, bool) {
+	switch status {
+	case LifecycleDeposited, LifecyclePendingInitialized:
+		return n.DepositSeen, true
+	case LifecyclePendingQueued:
+		return n.ActivationQueued, true
+	case LifecycleActiveOngoing:
+		return n.Activated, true
+	case LifecycleActiveExiting:
+		return n.ExitInitiated, true
+	case LifecycleExitedUnslashed, LifecycleExitedSlashed:
+		return n.Exited, true
+	case LifecycleWithdrawalPossible:
+		return n.Withdrawable, true
+	case LifecycleWithdrawalDone:
+		return n.FullyWithdrawn, true
+	}
+	return "", false
+}
+
+// ByName returns the correlation ID of a notification from its name, which is the correlation ID
+// without the network prefix such as validator-liveness
+func (n validatorNotifications) ByName(name string) (ValidatorNotification, bool) {
+	id := ValidatorNotification(n.network + "-" + name)
+	return id, slices.Contains(n.All(), id)
+}
+
+// ParseNotification returns the correlation ID of the notification named name on the network
+func ParseNotification(network, name string) (ValidatorNotification, bool) {
+	return newNotifications(network).ByName(name)
+}
+
 func InitNotifications(network string) {
-	Notifications = validatorNotifications{
+	Notifications = newNotifications(network)
+}
+
+func newNotifications(network string) validatorNotifications {
+	return validatorNotifications{
 		Liveness: ValidatorNotification(network + "-validator-liveness"),
 		Slashed:  ValidatorNotification(network + "-validator-slashed"),
 		Proposal: ValidatorNotification(network + "-block-proposal"),
+
+		SyncCommittee:     ValidatorNotification(network + "-sync-committee"),
+		SyncParticipation: ValidatorNotification(network + "-sync-participation"),
+
+		BeaconUnavailable: ValidatorNotification(network + "-beacon-unavailable"),
+		BeaconNotSynced:   ValidatorNotification(network + "-beacon-not-synced"),
+
+		UpcomingProposal: ValidatorNotification(network + "-upcoming-proposal"),
+
+		MaintenanceWindow: ValidatorNotification(network + "-maintenance-window"),
+
+		DepositSeen:      ValidatorNotification(network + "-validator-deposit"),
+		ActivationQueued: ValidatorNotification(network + "-validator-activation-queued"),
+		Activated:        ValidatorNotification(network + "-validator-activated"),
+		ExitInitiated:    ValidatorNotification(network + "-validator-exit-initiated"),
+		Exited:           ValidatorNotification(network + "-validator-exited"),
+		Withdrawable:     ValidatorNotification(network + "-validator-withdrawable"),
+		FullyWithdrawn:   ValidatorNotification(network + "-validator-withdrawn"),
+
+		network: network,
 	}
 }
@@ -0,0 +1,63 @@
+package domain
+
+import "math/big"
+
+// ProposalOutcome is the result of a proposer duty
+type ProposalOutcome string
+
+const (
+	// ProposalProposed means the block of the validator is part of the canonical chain
+	ProposalProposed ProposalOutcome = "proposed"
+	// ProposalMissed means no block of the validator was seen for the slot
+	ProposalMissed ProposalOutcome = "missed"
+	// ProposalOrphaned means the validator proposed a block that was reorged out of the canonical chain
+	ProposalOrphaned ProposalOutcome = "orphaned"
+)
+
+// SlotBlock is a block the beacon node knows for a slot, on the canonical chain or on a fork
+type SlotBlock struct {
+	Root          Root
+	ProposerIndex ValidatorIndex
+	Canonical     bool
+}
+
+// ExecutionPayload holds the execution layer details of a beacon block
+type ExecutionPayload struct {
+	BlockNumber  uint64
+	BlockHash    string
+	FeeRecipient string
+}
+
+// RelayPayload is a payload an MEV relay delivered to a proposer
+type RelayPayload struct {
+	Relay                string
+	BlockHash            string
+	BuilderPubkey        string
+	ProposerFeeRecipient string
+	// Value paid to the proposer in wei, nil if the relay reported an invalid one
+	Value *big.Int
+}
+
+// ProposalResult holds the execution layer details of a block proposed by one of our validators
+type ProposalResult struct {
+	ValidatorIndex ValidatorIndex `json:"validatorIndex"`
+	Slot           Slot           `json:"slot"`
+	BlockNumber    uint64         `json:"blockNumber"`
+	BlockHash      string         `json:"blockHash"`
+	// Address that received the block value. For MEV blocks it is the proposer fee recipient of the relay,
+	// as the payload fee recipient is usually the builder.
+	FeeRecipient string `json:"feeRecipient"`
+	// Value of the block for the proposer in wei, nil if it could not be determined
+	BlockValue *big.Int `json:"blockValue,omitempty"`
+	// Relay that delivered the payload, empty if the block was built locally
+	MevRelay      string `json:"mevRelay,omitempty"`
+	BuilderPubkey string `json:"builderPubkey,omitempty"`
+	// Fee recipient configured for the validator, empty if none is configured
+	ExpectedFeeRecipient string `json:"expectedFeeRecipient,omitempty"`
+	FeeRecipientMismatch bool   `json:"feeRecipientMismatch"`
+}
+
+// IsMev returns true if the block was built by an MEV builder and delivered by a relay
+func (p ProposalResult) IsMev() bool {
+	return p.MevRelay != ""
+}
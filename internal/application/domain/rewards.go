@@ -0,0 +1,70 @@
+package domain
+
+// Gwei is an amount of ether in gwei. It is signed because penalties are negative rewards.
+type Gwei int64
+
+// ETH converts the amount to ether
+func (g Gwei) ETH() float64 {
+	return float64(g) / 1e9
+}
+
+// ValidatorRewards compares what a validator earned with what it would have earned performing all its duties perfectly
+type ValidatorRewards struct {
+	ValidatorIndex ValidatorIndex `json:"validatorIndex"`
+	Earned         Gwei           `json:"earned"`
+	Ideal          Gwei           `json:"ideal"`
+}
+
+// Missed returns the rewards lost compared to the ideal ones, penalties included
+func (r ValidatorRewards) Missed() Gwei {
+	if r.Earned >= r.Ideal {
+		return 0
+	}
+	return r.Ideal - r.Earned
+}
+
+// Add accumulates other rewards of the same validator
+func (r *ValidatorRewards) Add(other ValidatorRewards) {
+	r.Earned += other.Earned
+	r.Ideal += other.Ideal
+}
+
+// BlockReward is the consensus reward paid to the proposer of a block
+type BlockReward struct {
+	ProposerIndex ValidatorIndex
+	Total         Gwei
+}
+
+// SyncCommitteeReward is the reward of a sync committee member for a block, negative if it missed its signature
+type SyncCommitteeReward struct {
+	ValidatorIndex ValidatorIndex
+	Reward         Gwei
+}
+
+// DailyRewards accumulates the rewards of each validator during a UTC day, formatted as 2006-01-02
+type DailyRewards struct {
+	Day        string                              `json:"day"`
+	Validators map[ValidatorIndex]ValidatorRewards `json:"validators"`
+}
+
+// Add accumulates the rewards of an epoch
+func (d *DailyRewards) Add(rewards []ValidatorRewards) {
+	if d.Validators == nil {
+		d.Validators = make(map[ValidatorIndex]ValidatorRewards)
+	}
+	for _, r := range rewards {
+		total := d.Validators[r.ValidatorIndex]
+		total.ValidatorIndex = r.ValidatorIndex
+		total.Add(r)
+		d.Validators[r.ValidatorIndex] = total
+	}
+}
+
+// Missed returns the rewards each of the given validators lost during the day
+func (d DailyRewards) Missed(validators []ValidatorIndex) map[ValidatorIndex]Gwei {
+	missed := make(map[ValidatorIndex]Gwei, len(validators))
+	for _, index := range validators {
+		missed[index] = d.Validators[index].Missed()
+	}
+	return missed
+}
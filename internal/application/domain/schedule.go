@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+// ChainTiming holds the chain parameters needed to convert slots and epochs to wall clock time
+type ChainTiming struct {
+	GenesisTime                  time.Time     `json:"genesisTime"`
+	SlotDuration                 time.Duration `json:"slotDuration"`
+	SlotsPerEpoch                uint64        `json:"slotsPerEpoch"`
+	EpochsPerSyncCommitteePeriod uint64        `json:"epochsPerSyncCommitteePeriod"`
+}
+
+// SlotAt returns the slot at a given time, 0 before genesis
+func (c ChainTiming) SlotAt(t time.Time) Slot {
+	if t.Before(c.GenesisTime) || c.SlotDuration == 0 {
+		return 0
+	}
+	return Slot(t.Sub(c.GenesisTime) / c.SlotDuration)
+}
+
+// SlotTime returns the time at which a slot starts
+func (c ChainTiming) SlotTime(slot Slot) time.Time {
+	return c.GenesisTime.Add(time.Duration(slot) * c.SlotDuration)
+}
+
+// EpochOf returns the epoch of a slot
+func (c ChainTiming) EpochOf(slot Slot) Epoch {
+	return Epoch(uint64(slot) / c.SlotsPerEpoch)
+}
+
+// EpochStart returns the first slot of an epoch
+func (c ChainTiming) EpochStart(epoch Epoch) Slot {
+	return Slot(uint64(epoch) * c.SlotsPerEpoch)
+}
+
+// SyncCommitteePeriod returns the sync committee period of an epoch
+func (c ChainTiming) SyncCommitteePeriod(epoch Epoch) uint64 {
+	return uint64(epoch) / c.EpochsPerSyncCommitteePeriod
+}
+
+// SupportedForks are the forks whose blocks the tracker can read. Fulu blocks have the same body as electra ones.
+var SupportedForks = []string{"electra", "fulu"}
+
+// Fork is a network upgrade scheduled at an epoch
+type Fork struct {
+	Name  string `json:"name"`
+	Epoch Epoch  `json:"epoch"`
+}
+
+// ChainSpec holds the parameters of the chain the beacon node runs, as discovered from its spec and genesis
+type ChainSpec struct {
+	ChainTiming
+	// CONFIG_NAME of the spec, such as mainnet
+	ConfigName string `json:"configName"`
+	// Scheduled forks sorted by epoch, starting with phase0 at genesis
+	Forks []Fork `json:"forks"`
+	// Bounds on the pending deposits processed per epoch since electra, 0 before it
+	DepositChurnLimit          Gwei   `json:"depositChurnLimit,omitempty"`
+	MaxPendingDepositsPerEpoch uint64 `json:"maxPendingDepositsPerEpoch,omitempty"`
+}
+
+// ForkAt returns the fork active at an epoch
+func (c ChainSpec) ForkAt(epoch Epoch) Fork {
+	fork := Fork{Name: "phase0"}
+	for _, f := range c.Forks {
+		if f.Epoch > epoch {
+			break
+		}
+		fork = f
+	}
+	return fork
+}
+
+// UpcomingProposal is a block proposal one of our validators is scheduled for
+type UpcomingProposal struct {
+	ValidatorIndex ValidatorIndex `json:"validatorIndex"`
+	Slot           Slot           `json:"slot"`
+	Epoch          Epoch          `json:"epoch"`
+	Time           time.Time      `json:"time"`
+}
+
+// UpcomingSyncCommittee is a sync committee period one of our validators was selected for
+type UpcomingSyncCommittee struct {
+	ValidatorIndex ValidatorIndex `json:"validatorIndex"`
+	Period         uint64         `json:"period"`
+	StartEpoch     Epoch          `json:"startEpoch"`
+	EndEpoch       Epoch          `json:"endEpoch"`
+	StartTime      time.Time      `json:"startTime"`
+}
+
+// DutyForecast lists the duties our validators are scheduled for in the near future
+type DutyForecast struct {
+	UpdatedAt      time.Time               `json:"updatedAt"`
+	CurrentSlot    Slot                    `json:"currentSlot"`
+	CurrentEpoch   Epoch                   `json:"currentEpoch"`
+	Proposals      []UpcomingProposal      `json:"proposals"`
+	SyncCommittees []UpcomingSyncCommittee `json:"syncCommittees"`
+}
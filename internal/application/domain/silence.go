@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Silence is a maintenance window during which notifications are not sent. The checks keep running and their
+// results are recorded, the notifications that were silenced are summarized when the window ends.
+type Silence struct {
+	ID string `json:"id"`
+	// Correlation IDs of the silenced notifications, all of them if empty
+	Notifications []ValidatorNotification `json:"notifications,omitempty"`
+	// Silenced validators, all of them if empty. Notifications that are not about validators,
+	// such as the beacon node ones, are only silenced by windows for all validators.
+	Validators []ValidatorIndex `json:"validators,omitempty"`
+	StartsAt   time.Time        `json:"startsAt"`
+	EndsAt     time.Time        `json:"endsAt"`
+	Reason     string           `json:"reason,omitempty"`
+	// Validators of each notification silenced so far, empty for notifications that are not about validators
+	Suppressed map[ValidatorNotification][]ValidatorIndex `json:"suppressed,omitempty"`
+}
+
+// ActiveAt returns true if the window is open at the given time
+func (s Silence) ActiveAt(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Covers returns true if the window silences the notification for the validator
+func (s Silence) Covers(notification ValidatorNotification, validator ValidatorIndex) bool {
+	return s.coversNotification(notification) && (len(s.Validators) == 0 || slices.Contains(s.Validators, validator))
+}
+
+// CoversAll returns true if the window silences the notification for all validators
+func (s Silence) CoversAll(notification ValidatorNotification) bool {
+	return s.coversNotification(notification) && len(s.Validators) == 0
+}
+
+func (s Silence) coversNotification(notification ValidatorNotification) bool {
+	return len(s.Notifications) == 0 || slices.Contains(s.Notifications, notification)
+}
+
+// RecordSuppressed adds the validators to the ones silenced for the notification and returns true if any was new
+func (s *Silence) RecordSuppressed(notification ValidatorNotification, validators []ValidatorIndex) bool {
+	if s.Suppressed == nil {
+		s.Suppressed = make(map[ValidatorNotification][]ValidatorIndex)
+	}
+	suppressed, found := s.Suppressed[notification]
+	changed := !found
+	for _, index := range validators {
+		if !slices.Contains(suppressed, index) {
+			suppressed = append(suppressed, index)
+			changed = true
+		}
+	}
+	slices.Sort(suppressed)
+	s.Suppressed[notification] = suppressed
+	return changed
+}
@@ -0,0 +1,45 @@
+package domain
+
+// CheckerState is the state the duties checker needs to survive restarts without re-sending notifications
+type CheckerState struct {
+	LastJustifiedEpoch Epoch                             `json:"lastJustifiedEpoch"`
+	LastProcessedEpoch Epoch                             `json:"lastProcessedEpoch"`
+	SlashedNotified    map[ValidatorIndex]bool           `json:"slashedNotified"`
+	LivenessStates     map[ValidatorIndex]*LivenessState `json:"livenessStates"`
+	// Correlation ID of the offline notification of each validator, which its recovery resolves. Nil in the
+	// state of previous versions, which notified each offline validator under its own correlation ID.
+	LivenessIncidents    map[ValidatorIndex]ValidatorNotification `json:"livenessIncidents"`
+	SyncCommitteeMembers map[ValidatorIndex]bool                  `json:"syncCommitteeMembers"`
+	DailyRewards         DailyRewards                             `json:"dailyRewards"`
+	// Lifecycle status of each tracked pubkey, so only the transitions are notified
+	LifecycleStatuses map[string]LifecycleStatus `json:"lifecycleStatuses,omitempty"`
+}
+
+// EpochResult holds the outcome of all the checks performed for an epoch
+type EpochResult struct {
+	Epoch           Epoch            `json:"epoch"`
+	Offline         []ValidatorIndex `json:"offline"`
+	Online          []ValidatorIndex `json:"online"`
+	Proposed        []ValidatorIndex `json:"proposed"`
+	MissedProposals []ValidatorIndex `json:"missedProposals"`
+	// Proposals of blocks that were reorged out of the canonical chain
+	OrphanedProposals []ValidatorIndex      `json:"orphanedProposals"`
+	Proposals         []ProposalResult      `json:"proposals,omitempty"`
+	Slashed           []ValidatorIndex      `json:"slashed"`
+	Attestations      []AttestationResult   `json:"attestations,omitempty"`
+	SyncCommittee     []SyncCommitteeResult `json:"syncCommittee,omitempty"`
+	Rewards           []ValidatorRewards    `json:"rewards,omitempty"`
+	// Compacted results only keep the incidents: offline validators, proposals and slashings
+	Compacted bool `json:"compacted,omitempty"`
+}
+
+// Compact drops the per-validator results of the validators that performed well, which are only kept for
+// recent epochs
+func (r EpochResult) Compact() EpochResult {
+	r.Online = nil
+	r.Attestations = nil
+	r.SyncCommittee = nil
+	r.Rewards = nil
+	r.Compacted = true
+	return r
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// TrackerStatus is a snapshot of what the duties checker is tracking and its latest results
+type TrackerStatus struct {
+	Pubkeys []string         `json:"pubkeys"`
+	Indices []ValidatorIndex `json:"indices"`
+	// Tag of each tagged validator, such as solo or lido
+	Tags               map[ValidatorIndex]string `json:"tags,omitempty"`
+	LastJustifiedEpoch Epoch                     `json:"lastJustifiedEpoch"`
+	LastProcessedEpoch Epoch                     `json:"lastProcessedEpoch"`
+	LastPollAt         time.Time                 `json:"lastPollAt"`
+	LastCheckAt        time.Time                 `json:"lastCheckAt"`
+	LastCheckSucceeded bool                      `json:"lastCheckSucceeded"`
+	LastResult         *EpochResult              `json:"lastResult,omitempty"`
+	// Chain parameters discovered from the beacon node, nil until it is first reachable
+	Chain *ChainSpec `json:"chain,omitempty"`
+	// Lifecycle of each pubkey known to the beacon node, including the ones that are not active
+	Lifecycles map[string]ValidatorLifecycle `json:"lifecycles,omitempty"`
+}
+
+// Lifecycle returns the lifecycle of the validator with an index, false if its pubkey is not tracked
+func (s TrackerStatus) Lifecycle(index ValidatorIndex) (ValidatorLifecycle, bool) {
+	for _, lifecycle := range s.Lifecycles {
+		if lifecycle.Validator != nil && lifecycle.Validator.Index == index {
+			return lifecycle, true
+		}
+	}
+	return ValidatorLifecycle{}, false
+}
+
+// ValidatorStatus is the latest known state of a single validator
+type ValidatorStatus struct {
+	Index         ValidatorIndex       `json:"index"`
+	Tag           string               `json:"tag,omitempty"`
+	Epoch         Epoch                `json:"epoch"`
+	Live          bool                 `json:"live"`
+	Slashed       bool                 `json:"slashed"`
+	Proposed      bool                 `json:"proposed"`
+	MissedBlock   bool                 `json:"missedBlock"`
+	OrphanedBlock bool                 `json:"orphanedBlock"`
+	Proposal      *ProposalResult      `json:"proposal,omitempty"`
+	Attestation   *AttestationResult   `json:"attestation,omitempty"`
+	SyncCommittee *SyncCommitteeResult `json:"syncCommittee,omitempty"`
+	Rewards       *ValidatorRewards    `json:"rewards,omitempty"`
+	// Nil for the validators tracked by index
+	Lifecycle *ValidatorLifecycle `json:"lifecycle,omitempty"`
+}
+
+// ProposalOutcome returns the outcome of the proposal of a validator in this epoch result, false if it had none
+func (r EpochResult) ProposalOutcome(index ValidatorIndex) (ProposalOutcome, bool) {
+	switch {
+	case slices.Contains(r.Proposed, index):
+		return ProposalProposed, true
+	case slices.Contains(r.OrphanedProposals, index):
+		return ProposalOrphaned, true
+	case slices.Contains(r.MissedProposals, index):
+		return ProposalMissed, true
+	}
+	return "", false
+}
+
+// Validator returns the status of a validator in this epoch result. The boolean is false if it was not checked.
+func (r EpochResult) Validator(index ValidatorIndex) (ValidatorStatus, bool) {
+	status := ValidatorStatus{Index: index, Epoch: r.Epoch}
+	found := false
+	for _, i := range r.Online {
+		if i == index {
+			status.Live = true
+			found = true
+		}
+	}
+	for _, i := range r.Offline {
+		if i == index {
+			found = true
+		}
+	}
+	for _, i := range r.Slashed {
+		if i == index {
+			status.Slashed = true
+		}
+	}
+	for _, i := range r.Proposed {
+		if i == index {
+			status.Proposed = true
+		}
+	}
+	for _, i := range r.MissedProposals {
+		if i == index {
+			status.MissedBlock = true
+		}
+	}
+	for _, i := range r.OrphanedProposals {
+		if i == index {
+			status.OrphanedBlock = true
+		}
+	}
+	for _, p := range r.Proposals {
+		if p.ValidatorIndex == index {
+			status.Proposal = &p
+		}
+	}
+	for _, a := range r.Attestations {
+		if a.ValidatorIndex == index {
+			status.Attestation = &a
+		}
+	}
+	for _, s := range r.SyncCommittee {
+		if s.ValidatorIndex == index {
+			status.SyncCommittee = &s
+		}
+	}
+	for _, rw := range r.Rewards {
+		if rw.ValidatorIndex == index {
+			status.Rewards = &rw
+		}
+	}
+	return status, found
+}
@@ -6,6 +6,7 @@ package domain
 type Epoch uint64
 type Slot uint64
 type ValidatorIndex uint64
+type Root [32]byte
 
 // --------------------------------------------------------
 
@@ -21,6 +22,29 @@ type Attestation struct {
 	DataSlot        Slot
 	CommitteeBits   []byte
 	AggregationBits []byte
+	BeaconBlockRoot Root
+	SourceEpoch     Epoch
+	SourceRoot      Root
+	TargetEpoch     Epoch
+	TargetRoot      Root
+}
+
+// AttestationResult is the outcome of a single attestation duty once the inclusion window is over
+type AttestationResult struct {
+	ValidatorIndex ValidatorIndex `json:"validatorIndex"`
+	DutySlot       Slot           `json:"dutySlot"`
+	Included       bool           `json:"included"`
+	InclusionSlot  Slot           `json:"inclusionSlot"`
+	InclusionDelay uint64         `json:"inclusionDelay"`
+	CorrectHead    bool           `json:"correctHead"`
+	CorrectTarget  bool           `json:"correctTarget"`
+	CorrectSource  bool           `json:"correctSource"`
+}
+
+// Checkpoint is the first block of an epoch, which attestations vote for as source and target
+type Checkpoint struct {
+	Epoch Epoch
+	Root  Root
 }
 
 type CommitteeSizeMap map[CommitteeIndex]int
@@ -33,3 +57,26 @@ type ProposerDuty struct {
 	Slot           Slot
 	ValidatorIndex ValidatorIndex
 }
+
+// --------------------------------------------------------
+
+// Sync committee-related types
+type SyncCommitteeDuty struct {
+	ValidatorIndex                ValidatorIndex
+	ValidatorSyncCommitteeIndices []uint64
+}
+
+// SyncCommitteeResult counts the sync committee signatures of a validator over an epoch
+type SyncCommitteeResult struct {
+	ValidatorIndex ValidatorIndex `json:"validatorIndex"`
+	Expected       uint64         `json:"expected"`
+	Missed         uint64         `json:"missed"`
+}
+
+// Participation returns the percentage of expected sync committee signatures that were included
+func (r SyncCommitteeResult) Participation() float64 {
+	if r.Expected == 0 {
+		return 100
+	}
+	return float64(r.Expected-r.Missed) * 100 / float64(r.Expected)
+}
@@ -2,22 +2,50 @@ package ports
 
 import (
 	"context"
+	"errors"
 
 	"github.com/dappnode/validator-tracker/internal/application/domain"
 )
 
+// ErrBeaconUnavailable is returned while there is no connection to the beacon node
+var ErrBeaconUnavailable = errors.New("beacon node unavailable")
+
 // ports/beaconchain_adapter.go
 type BeaconChainAdapter interface {
 	GetFinalizedEpoch(ctx context.Context) (domain.Epoch, error)
 	GetJustifiedEpoch(ctx context.Context) (domain.Epoch, error)
 	GetValidatorDutiesBatch(ctx context.Context, epoch domain.Epoch, validatorIndices []domain.ValidatorIndex) ([]domain.ValidatorDuty, error)
-	GetCommitteeSizeMap(ctx context.Context, slot domain.Slot) (domain.CommitteeSizeMap, error)
+	// GetEpochCommitteeSizes returns the size of each attestation committee by slot, for every slot of the epoch
+	GetEpochCommitteeSizes(ctx context.Context, epoch domain.Epoch) (map[domain.Slot]domain.CommitteeSizeMap, error)
+	// GetJustifiedCheckpoint returns the current justified checkpoint of the state at a slot
+	GetJustifiedCheckpoint(ctx context.Context, slot domain.Slot) (domain.Checkpoint, error)
 	GetBlockAttestations(ctx context.Context, slot domain.Slot) ([]domain.Attestation, error)
-	GetValidatorIndicesByPubkeys(ctx context.Context, pubkeys []string) ([]domain.ValidatorIndex, error)
+	GetBlockRoot(ctx context.Context, slot domain.Slot) (domain.Root, bool, error)
+	GetSlotsPerEpoch(ctx context.Context) (uint64, error)
+	GetChainSpec(ctx context.Context) (domain.ChainSpec, error)
+	GetSyncStatus(ctx context.Context) (domain.SyncStatus, error)
+	GetNodeHealth(ctx context.Context) (domain.NodeHealth, error)
+	GetPeerCount(ctx context.Context) (uint64, error)
+	// GetValidatorIndicesByPubkeys returns the index of each validator by its normalized pubkey, whatever its status
+	GetValidatorIndicesByPubkeys(ctx context.Context, pubkeys []string) (map[string]domain.ValidatorIndex, error)
+	GetValidatorStates(ctx context.Context, indices []domain.ValidatorIndex) (map[domain.ValidatorIndex]domain.ValidatorState, error)
+	// GetPendingDeposits returns the deposits waiting to be processed, in processing order
+	GetPendingDeposits(ctx context.Context) ([]domain.PendingDeposit, error)
 	GetSlashedValidators(ctx context.Context, indices []domain.ValidatorIndex) ([]domain.ValidatorIndex, error)
 
 	GetProposerDuties(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.ProposerDuty, error)
-	DidProposeBlock(ctx context.Context, slot domain.Slot) (bool, error)
+	// GetSlotBlocks returns every block known for a slot, including the ones that are not canonical
+	GetSlotBlocks(ctx context.Context, slot domain.Slot) ([]domain.SlotBlock, error)
+	GetBlockExecutionPayload(ctx context.Context, slot domain.Slot) (domain.ExecutionPayload, bool, error)
+
+	GetSyncCommitteeDuties(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.SyncCommitteeDuty, error)
+	GetBlockSyncAggregate(ctx context.Context, slot domain.Slot) ([]byte, bool, error)
 
 	GetValidatorsLiveness(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) (map[domain.ValidatorIndex]bool, error)
+
+	GetAttestationRewards(ctx context.Context, epoch domain.Epoch, indices []domain.ValidatorIndex) ([]domain.ValidatorRewards, error)
+	GetBlockReward(ctx context.Context, slot domain.Slot) (domain.BlockReward, bool, error)
+	GetSyncCommitteeRewards(ctx context.Context, slot domain.Slot, indices []domain.ValidatorIndex) ([]domain.SyncCommitteeReward, bool, error)
+
+	SubscribeChainEvents(ctx context.Context) (<-chan domain.ChainEvent, error)
 }
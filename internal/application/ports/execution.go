@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+	"math/big"
+)
+
+// ExecutionAdapter queries the execution client
+type ExecutionAdapter interface {
+	// GetBlockPriorityFees returns the priority fees in wei paid to the fee recipient of a block
+	GetBlockPriorityFees(ctx context.Context, blockHash string) (*big.Int, error)
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/dappnode/validator-tracker/internal/application/domain"
+
+// KeySource lists the validators to track. It is implemented by the Brain and the Web3Signer adapters,
+// static and file based lists, and combinations of those. Pubkeys are normalized.
+type KeySource interface {
+	GetValidatorKeys() (domain.ValidatorKeys, error)
+}
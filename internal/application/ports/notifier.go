@@ -1,9 +1,26 @@
 package ports
 
-import "github.com/dappnode/validator-tracker/internal/application/domain"
+import (
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+)
 
 type NotifierPort interface {
-	SendValidatorLivenessNot(validators []domain.ValidatorIndex, epoch domain.Epoch, live bool) error
+	SendValidatorLivenessNot(incident domain.ValidatorNotification, validators []domain.ValidatorIndex, epoch domain.Epoch, live bool, missedRewards map[domain.ValidatorIndex]domain.Gwei) error
 	SendValidatorsSlashedNot(validators []domain.ValidatorIndex, epoch domain.Epoch) error
-	SendBlockProposalNot(validators []domain.ValidatorIndex, epoch domain.Epoch, proposed bool) error
+	SendBlockProposalNot(validators []domain.ValidatorIndex, epoch domain.Epoch, outcome domain.ProposalOutcome, missedRewards map[domain.ValidatorIndex]domain.Gwei, proposals []domain.ProposalResult) error
+	SendSyncCommitteeSelectedNot(validators []domain.ValidatorIndex, epoch domain.Epoch) error
+	SendSyncParticipationNot(validators []domain.ValidatorIndex, epoch domain.Epoch, threshold float64) error
+	SendBeaconUnavailableNot(since time.Time, available bool) error
+	SendBeaconNotSyncedNot(reason string, synced bool) error
+	SendUpcomingProposalNot(validator domain.ValidatorIndex, slot domain.Slot, at time.Time) error
+	SendMaintenanceEndedNot(silence domain.Silence) error
+	// SendValidatorLifecycleNot notifies that validators reached a status of their lifecycle, such as activated
+	SendValidatorLifecycleNot(status domain.LifecycleStatus, validators []domain.ValidatorLifecycle) error
+	// ForTag returns a notifier for the validators with a tag, which labels its notifications with it.
+	// Deliveries of both count for LastDeliveryError.
+	ForTag(tag string) NotifierPort
+	// LastDeliveryError returns the error of the last notification sent, nil if it was delivered or none was sent yet
+	LastDeliveryError() error
 }
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+)
+
+// RelayAdapter queries the data APIs of the MEV relays
+type RelayAdapter interface {
+	// GetDeliveredPayloads returns the payloads the relays delivered for a slot, none if the block was built locally
+	GetDeliveredPayloads(ctx context.Context, slot domain.Slot) ([]domain.RelayPayload, error)
+}
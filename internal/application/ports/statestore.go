@@ -0,0 +1,15 @@
+package ports
+
+import "github.com/dappnode/validator-tracker/internal/application/domain"
+
+// StateStore persists the checker state, per-epoch results, maintenance windows and validator indices across restarts
+type StateStore interface {
+	LoadCheckerState() (domain.CheckerState, bool, error)
+	SaveCheckerState(state domain.CheckerState) error
+	SaveEpochResult(result domain.EpochResult) error
+	GetEpochResult(epoch domain.Epoch) (domain.EpochResult, bool, error)
+	LoadSilences() ([]domain.Silence, error)
+	SaveSilences(silences []domain.Silence) error
+	LoadValidatorIndices() (map[string]domain.ValidatorIndex, error)
+	SaveValidatorIndices(indices map[string]domain.ValidatorIndex) error
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// AttestationChecker matches attester duties against the attestations included on chain to
+// measure the quality of each attestation, not only whether the validator was seen.
+type AttestationChecker struct {
+	Beacon ports.BeaconChainAdapter
+}
+
+// CheckEpoch returns one result per attester duty of the given validators in the given epoch.
+// Since Deneb an attestation can be included until the end of the next epoch, so the epoch
+// after the one being checked must already be over for the results to be final.
+func (c *AttestationChecker) CheckEpoch(
+	ctx context.Context,
+	epoch domain.Epoch,
+	indices []domain.ValidatorIndex,
+) ([]domain.AttestationResult, error) {
+	if len(indices) == 0 {
+		logger.Debug("Called CheckEpoch with no validator indices, nothing to check.")
+		return nil, nil
+	}
+
+	slotsPerEpoch, err := c.Beacon.GetSlotsPerEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	duties, err := c.Beacon.GetValidatorDutiesBatch(ctx, epoch, indices)
+	if err != nil {
+		return nil, err
+	}
+	if len(duties) == 0 {
+		logger.Warn("No attester duties for any validators in epoch %d", epoch)
+		return nil, nil
+	}
+
+	// Committee sizes are needed to locate each validator in the aggregation bits
+	committeeSizes, err := c.Beacon.GetEpochCommitteeSizes(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.AttestationResult, len(duties))
+	for i, duty := range duties {
+		results[i] = domain.AttestationResult{
+			ValidatorIndex: duty.ValidatorIndex,
+			DutySlot:       duty.Slot,
+		}
+	}
+
+	roots := &canonicalRoots{beacon: c.Beacon, cache: make(map[domain.Slot]domain.Root)}
+	firstSlot := domain.Slot(uint64(epoch) * slotsPerEpoch)
+	targetRoot, err := roots.at(ctx, firstSlot)
+	if err != nil {
+		return nil, err
+	}
+	// A correct source is the justified checkpoint as of the start of the epoch
+	source, err := c.Beacon.GetJustifiedCheckpoint(ctx, firstSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := len(duties)
+	lastInclusionSlot := firstSlot + domain.Slot(2*slotsPerEpoch) - 1
+	for slot := firstSlot + 1; slot <= lastInclusionSlot && pending > 0; slot++ {
+		attestations, err := c.Beacon.GetBlockAttestations(ctx, slot)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, att := range attestations {
+			if att.TargetEpoch != epoch {
+				continue
+			}
+			for i, duty := range duties {
+				if results[i].Included || duty.Slot != att.DataSlot || duty.Slot >= slot {
+					continue
+				}
+				if !attestationIncludesDuty(att, duty, committeeSizes[duty.Slot]) {
+					continue
+				}
+
+				headRoot, err := roots.at(ctx, duty.Slot)
+				if err != nil {
+					return nil, err
+				}
+				results[i].Included = true
+				results[i].InclusionSlot = slot
+				results[i].InclusionDelay = uint64(slot - duty.Slot)
+				results[i].CorrectHead = att.BeaconBlockRoot == headRoot
+				results[i].CorrectTarget = att.TargetRoot == targetRoot
+				results[i].CorrectSource = att.SourceEpoch == source.Epoch && att.SourceRoot == source.Root
+				pending--
+			}
+		}
+	}
+
+	for _, r := range results {
+		if !r.Included {
+			logger.Warn("❌ Validator %d missed its attestation for slot %d", r.ValidatorIndex, r.DutySlot)
+			continue
+		}
+		logger.Info("✅ Validator %d attestation for slot %d included at slot %d (delay=%d, head=%v, target=%v, source=%v)",
+			r.ValidatorIndex, r.DutySlot, r.InclusionSlot, r.InclusionDelay, r.CorrectHead, r.CorrectTarget, r.CorrectSource)
+	}
+	return results, nil
+}
+
+// attestationIncludesDuty checks whether the aggregation bits of an attestation contain the validator of the duty.
+// Since Electra a single attestation may aggregate several committees of the same slot: committee bits flag which
+// committees are included and the aggregation bits are the concatenation of those committees in ascending order.
+func attestationIncludesDuty(att domain.Attestation, duty domain.ValidatorDuty, sizes domain.CommitteeSizeMap) bool {
+	if !isBitSet(att.CommitteeBits, uint64(duty.CommitteeIndex)) {
+		return false
+	}
+	var offset uint64
+	for committeeIndex := domain.CommitteeIndex(0); committeeIndex < duty.CommitteeIndex; committeeIndex++ {
+		if isBitSet(att.CommitteeBits, uint64(committeeIndex)) {
+			offset += uint64(sizes[committeeIndex])
+		}
+	}
+	return isBitSet(att.AggregationBits, offset+duty.ValidatorCommitteeIdx)
+}
+
+// isBitSet reads a little-endian SSZ bitfield
+func isBitSet(bits []byte, i uint64) bool {
+	if i/8 >= uint64(len(bits)) {
+		return false
+	}
+	return bits[i/8]&(1<<(i%8)) != 0
+}
+
+// canonicalRoots resolves the root a correct vote should point to for a slot,
+// which is the root of the latest block at or before that slot.
+type canonicalRoots struct {
+	beacon ports.BeaconChainAdapter
+	cache  map[domain.Slot]domain.Root
+}
+
+func (r *canonicalRoots) at(ctx context.Context, slot domain.Slot) (domain.Root, error) {
+	if root, ok := r.cache[slot]; ok {
+		return root, nil
+	}
+	for s := slot; ; s-- {
+		root, found, err := r.beacon.GetBlockRoot(ctx, s)
+		if err != nil {
+			return domain.Root{}, err
+		}
+		if found || s == 0 {
+			r.cache[slot] = root
+			return root, nil
+		}
+	}
+}
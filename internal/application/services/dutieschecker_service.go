@@ -2,51 +2,169 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dappnode/validator-tracker/internal/application/domain"
 	"github.com/dappnode/validator-tracker/internal/application/ports"
 	"github.com/dappnode/validator-tracker/internal/logger"
+	"github.com/dappnode/validator-tracker/internal/metrics"
 )
 
+// CheckerSettings are the settings of the duties checker that can be reloaded while it runs
+type CheckerSettings struct {
+	PollInterval time.Duration
+	// Percentage of sync committee signatures below which a notification is sent
+	SyncParticipationThreshold float64
+	// Epochs skipped while the beacon node was unreachable are backfilled, up to MaxLookbackEpochs
+	MaxLookbackEpochs uint64
+	// A notification is sent once the beacon node is unreachable for BeaconOutageAlertAfter, 0 disables it
+	BeaconOutageAlertAfter time.Duration
+	// Checks are skipped while the beacon node is syncing, optimistic, without execution client or with less than MinBeaconPeers
+	MinBeaconPeers uint64
+	// A validator is alerted as offline after MissedEpochsToAlert consecutive missed epochs and resolved after LiveEpochsToResolve live ones
+	MissedEpochsToAlert uint64
+	LiveEpochsToResolve uint64
+
+	// The optional checks can be turned off without removing their checker
+	DisableAttestations    bool
+	DisableSyncCommittee   bool
+	DisableRewards         bool
+	DisableProposalDetails bool
+}
+
 type DutiesChecker struct {
-	Beacon      ports.BeaconChainAdapter
-	Brain       ports.BrainAdapter
+	Beacon ports.BeaconChainAdapter
+	Keys   ports.KeySource
+	// Notifies about the beacon node, the notifications about validators go through Tags
 	Notifier    ports.NotifierPort
 	Dappmanager ports.DappManagerPort
+	// Routes the notifications about validators by their tag, such as solo or lido
+	Tags *TagRouter
+	// Resolves the pubkeys of the key source to their validators and their lifecycle
+	Resolver *IndexResolver
+	// CONFIG_NAME expected in the chain spec of the beacon node, not checked if empty as for custom networks
+	Network string
+
+	CheckerSettings
+	reload pendingSettings[CheckerSettings]
+
+	// Optional, attestation quality is not checked if nil
+	Attestations *AttestationChecker
+	// Optional, sync committee participation is not checked if nil
+	SyncCommittee *SyncCommitteeChecker
+	// Optional, missed rewards are not computed nor notified if nil
+	Rewards *RewardsChecker
+	// Optional, the execution payload of proposed blocks is not inspected if nil
+	Proposals *ProposalChecker
+	// Rewards accumulated during the current UTC day
+	dailyRewards domain.DailyRewards
+
+	// Optional, state is only kept in memory if nil
+	Store ports.StateStore
+	// Optional, notifications are never silenced if nil
+	Silencer *Silencer
 
-	PollInterval       time.Duration
 	lastJustifiedEpoch domain.Epoch
 	lastRunHadError    bool
+	// Epochs up to this one were already checked and are checked again because of a reorg
+	reevaluateUpTo domain.Epoch
+	// The chain spec is read once, on the first check the beacon node is reachable
+	chainDiscovered bool
+	// Polling is skipped while beacon node events keep arriving
+	lastEventAt time.Time
+
+	beaconUnavailableSince  time.Time
+	beaconOutageNotified    bool
+	beaconNotSyncedNotified bool
+	lastProcessedEpoch      domain.Epoch
 
 	SlashedNotified map[domain.ValidatorIndex]bool
 
-	// Tracking previous states for notifications
-	PreviouslyAllLive bool
-	PreviouslyOffline bool
+	// Per-validator liveness used for notifications
+	LivenessStates map[domain.ValidatorIndex]*domain.LivenessState
+	// Correlation ID of the notification each offline validator was notified under, resolved on its recovery
+	livenessIncidents map[domain.ValidatorIndex]domain.ValidatorNotification
+
+	// Sync committee members in the last checked epoch, used to notify only newly selected validators
+	syncCommitteeMembers map[domain.ValidatorIndex]bool
+
+	// Last lifecycle status of each tracked pubkey, used to notify only the transitions
+	lifecycleStatuses map[string]domain.LifecycleStatus
+
+	// Notifications sent off the check path, in order, by a single goroutine running along Run
+	background chan func()
+
+	// Snapshot exposed to the API, guarded by statusMu as it is read from other goroutines
+	statusMu sync.RWMutex
+	status   domain.TrackerStatus
 }
 
+// Run checks new justified epochs as soon as the beacon node streams an epoch transition, a new
+// finalized checkpoint or a reorg. Polling every PollInterval is used as fallback while no events arrive.
 func (a *DutiesChecker) Run(ctx context.Context) {
 	ticker := time.NewTicker(a.PollInterval)
 	defer ticker.Stop()
 
+	// The queued notifications are still sent on shutdown, Run returns once they are
+	a.background = make(chan func(), backgroundQueueSize)
+	var sender sync.WaitGroup
+	sender.Add(1)
+	go func() {
+		defer sender.Done()
+		for send := range a.background {
+			send()
+		}
+	}()
+	defer func() {
+		close(a.background)
+		if queued := len(a.background); queued > 0 {
+			logger.Info("Sending %d queued notifications before shutting down", queued)
+		}
+		sender.Wait()
+	}()
+
+	// A nil channel never receives, leaving only the polling until the subscription succeeds
+	events := a.subscribeChainEvents(ctx)
+
 	for {
 		select {
 		case <-ticker.C:
-			justifiedEpoch, err := a.Beacon.GetJustifiedEpoch(ctx)
-			if err != nil {
-				logger.Error("Error fetching justified epoch: %v", err)
-				a.lastRunHadError = true
-				continue
+			a.applyReload(ticker)
+			if events == nil {
+				events = a.subscribeChainEvents(ctx)
 			}
-
-			if justifiedEpoch == a.lastJustifiedEpoch && !a.lastRunHadError {
-				logger.Debug("Justified epoch %d unchanged and last run was successful, skipping check.", justifiedEpoch)
+			if time.Since(a.lastEventAt) < a.PollInterval {
+				logger.Debug("Beacon node events are being received, skipping poll.")
 				continue
 			}
+			a.checkJustifiedEpoch(ctx)
 
-			a.lastJustifiedEpoch = justifiedEpoch
-			a.lastRunHadError = a.performChecks(ctx, justifiedEpoch) != nil
+		case event := <-events:
+			a.applyReload(ticker)
+			a.lastEventAt = time.Now()
+			switch event.Type {
+			case domain.ChainEventHead:
+				if event.EpochTransition {
+					a.checkJustifiedEpoch(ctx)
+				}
+			case domain.ChainEventFinalizedCheckpoint:
+				a.checkJustifiedEpoch(ctx)
+			case domain.ChainEventReorg:
+				// Reorgs of already checked epochs are rare but make their results stale, so check them again.
+				// Only the outcomes the reorg changed are notified again.
+				if event.Epoch > 0 && event.Epoch <= a.lastProcessedEpoch {
+					logger.Warn("Reorg of depth %d at slot %d affects checked epoch %d, re-evaluating it.", event.Depth, event.Slot, event.Epoch)
+					a.reevaluateUpTo = max(a.reevaluateUpTo, a.lastProcessedEpoch)
+					a.lastProcessedEpoch = event.Epoch - 1
+				}
+				a.checkJustifiedEpoch(ctx)
+			}
 
 		case <-ctx.Done():
 			return
@@ -54,30 +172,401 @@ func (a *DutiesChecker) Run(ctx context.Context) {
 	}
 }
 
-func (a *DutiesChecker) performChecks(ctx context.Context, justifiedEpoch domain.Epoch) error {
-	logger.Info("New justified epoch %d detected.", justifiedEpoch)
+// Reload replaces the settings of the checker, they are applied before its next check
+func (a *DutiesChecker) Reload(settings CheckerSettings) {
+	a.reload.set(settings)
+}
+
+// applyReload applies the reloaded settings, if any. Must be called from the checker goroutine.
+func (a *DutiesChecker) applyReload(ticker *time.Ticker) {
+	settings, ok := a.reload.take()
+	if !ok {
+		return
+	}
+	a.CheckerSettings = settings
+	ticker.Reset(a.PollInterval)
+	logger.Info("Duties checker settings reloaded")
+}
+
+func (a *DutiesChecker) subscribeChainEvents(ctx context.Context) <-chan domain.ChainEvent {
+	events, err := a.Beacon.SubscribeChainEvents(ctx)
+	if err != nil {
+		logger.Warn("Error subscribing to beacon node events, falling back to polling: %v", err)
+		return nil
+	}
+	return events
+}
+
+// checkJustifiedEpoch fetches the justified epoch and processes it if it was not already
+func (a *DutiesChecker) checkJustifiedEpoch(ctx context.Context) {
+	a.updateStatus(func(s *domain.TrackerStatus) { s.LastPollAt = time.Now() })
+
+	justifiedEpoch, err := a.Beacon.GetJustifiedEpoch(ctx)
+	if err != nil {
+		logger.Error("Error fetching justified epoch: %v", err)
+		a.lastRunHadError = true
+		a.trackBeaconOutage(ctx, false)
+		return
+	}
+	a.trackBeaconOutage(ctx, true)
+	if !a.chainDiscovered {
+		a.discoverChain(ctx)
+	}
+
+	if reason := a.beaconNotReadyReason(ctx); reason != "" {
+		logger.Warn("Skipping checks for justified epoch %d because the %s.", justifiedEpoch, reason)
+		a.lastRunHadError = true
+		a.trackBeaconNotSynced(ctx, reason)
+		return
+	}
+	a.trackBeaconNotSynced(ctx, "")
+
+	if justifiedEpoch == a.lastJustifiedEpoch && !a.lastRunHadError && a.lastProcessedEpoch >= justifiedEpoch {
+		logger.Debug("Justified epoch %d unchanged and last run was successful, skipping check.", justifiedEpoch)
+		return
+	}
+
+	a.lastJustifiedEpoch = justifiedEpoch
+	a.lastRunHadError = a.processEpochs(ctx, justifiedEpoch) != nil
+	a.updateStatus(func(s *domain.TrackerStatus) {
+		s.LastJustifiedEpoch = a.lastJustifiedEpoch
+		s.LastProcessedEpoch = a.lastProcessedEpoch
+		s.LastCheckAt = time.Now()
+		s.LastCheckSucceeded = !a.lastRunHadError
+	})
+}
+
+// discoverChain reads the chain parameters of the beacon node, warning if it runs another network than the
+// configured one or a fork whose blocks cannot be read. It is retried on the next check if it fails.
+func (a *DutiesChecker) discoverChain(ctx context.Context) {
+	spec, err := a.Beacon.GetChainSpec(ctx)
+	if err != nil {
+		logger.Warn("Error fetching chain spec of the beacon node: %v", err)
+		return
+	}
+	a.chainDiscovered = true
+	a.updateStatus(func(s *domain.TrackerStatus) { s.Chain = &spec })
+
+	fork := spec.ForkAt(spec.EpochOf(spec.SlotAt(time.Now())))
+	logger.Info("Beacon node runs network %s at fork %s: genesis at %s, %s slots, %d slots per epoch, forks %v",
+		spec.ConfigName, fork.Name, spec.GenesisTime.Format(time.RFC3339), spec.SlotDuration, spec.SlotsPerEpoch, spec.Forks)
+	if a.Network != "" && spec.ConfigName != a.Network {
+		logger.Warn("Beacon node runs network %s but the tracker is configured for %s.", spec.ConfigName, a.Network)
+	}
+	if !slices.Contains(domain.SupportedForks, fork.Name) {
+		logger.Warn("Beacon node is at fork %s, blocks are only read for forks %v so proposal and attestation details may be missing.", fork.Name, domain.SupportedForks)
+	}
+}
+
+// trackBeaconOutage notifies once the beacon node has been unreachable for BeaconOutageAlertAfter,
+// and again when it is reachable after that notification.
+func (a *DutiesChecker) trackBeaconOutage(ctx context.Context, available bool) {
+	if available {
+		if a.beaconOutageNotified {
+			a.sendBeaconOutageNot(ctx, true)
+		}
+		a.beaconUnavailableSince = time.Time{}
+		a.beaconOutageNotified = false
+		return
+	}
+
+	if a.beaconUnavailableSince.IsZero() {
+		a.beaconUnavailableSince = time.Now()
+	}
+	if a.BeaconOutageAlertAfter > 0 && !a.beaconOutageNotified && time.Since(a.beaconUnavailableSince) >= a.BeaconOutageAlertAfter {
+		// Not marked as notified while silenced, so it is sent if the outage lasts beyond the maintenance window
+		if a.silenced(domain.Notifications.BeaconUnavailable) {
+			return
+		}
+		a.sendBeaconOutageNot(ctx, false)
+		a.beaconOutageNotified = true
+	}
+}
 
+func (a *DutiesChecker) sendBeaconOutageNot(ctx context.Context, available bool) {
 	notificationsEnabled, err := a.Dappmanager.GetNotificationsEnabled(ctx)
 	if err != nil {
 		logger.Warn("Error fetching notifications enabled, notification will not be sent: %v", err)
+		return
 	}
+	if !notificationsEnabled[domain.Notifications.BeaconUnavailable] {
+		return
+	}
+	if err := a.Notifier.SendBeaconUnavailableNot(a.beaconUnavailableSince, available); err != nil {
+		logger.Warn("Error sending beacon unavailable notification: %v", err)
+	}
+}
 
-	pubkeys, err := a.Brain.GetValidatorPubkeys()
+// beaconNotReadyReason returns why the beacon node answers cannot be trusted, or an empty string if they can.
+// A node that cannot be queried for its state is trusted, the checks themselves will surface its errors.
+func (a *DutiesChecker) beaconNotReadyReason(ctx context.Context) string {
+	syncStatus, err := a.Beacon.GetSyncStatus(ctx)
 	if err != nil {
-		logger.Error("Error fetching pubkeys from brain: %v", err)
-		return err
+		logger.Warn("Error fetching beacon node sync status: %v", err)
+	} else {
+		switch {
+		case syncStatus.IsSyncing:
+			return fmt.Sprintf("beacon node is syncing (%d slots behind)", syncStatus.SyncDistance)
+		case syncStatus.IsOptimistic:
+			return "beacon node is optimistic"
+		case syncStatus.ElOffline:
+			return "execution client of the beacon node is offline"
+		}
 	}
 
-	if len(pubkeys) == 0 {
-		logger.Debug("No pubkeys found in brain for epoch %d, nothing to check.", justifiedEpoch)
+	health, err := a.Beacon.GetNodeHealth(ctx)
+	if err != nil {
+		logger.Warn("Error fetching beacon node health: %v", err)
+	} else if health != domain.NodeHealthReady {
+		return fmt.Sprintf("beacon node health is %s", health)
+	}
+
+	if a.MinBeaconPeers > 0 {
+		peers, err := a.Beacon.GetPeerCount(ctx)
+		if err != nil {
+			logger.Warn("Error fetching beacon node peer count: %v", err)
+		} else if peers < a.MinBeaconPeers {
+			return fmt.Sprintf("beacon node has only %d peers", peers)
+		}
+	}
+	return ""
+}
+
+// trackBeaconNotSynced notifies once when checks start being skipped, and again when they resume
+func (a *DutiesChecker) trackBeaconNotSynced(ctx context.Context, reason string) {
+	synced := reason == ""
+	// Nothing changed: either still synced, or still not synced and already notified
+	if synced != a.beaconNotSyncedNotified {
+		return
+	}
+	// Not marked as notified while silenced, so it is sent if the node is still not synced after the maintenance window
+	if !synced && a.silenced(domain.Notifications.BeaconNotSynced) {
+		return
+	}
+	a.beaconNotSyncedNotified = !synced
+
+	notificationsEnabled, err := a.Dappmanager.GetNotificationsEnabled(ctx)
+	if err != nil {
+		logger.Warn("Error fetching notifications enabled, notification will not be sent: %v", err)
+		return
+	}
+	if !notificationsEnabled[domain.Notifications.BeaconNotSynced] {
+		return
+	}
+	if err := a.Notifier.SendBeaconNotSyncedNot(reason, synced); err != nil {
+		logger.Warn("Error sending beacon not synced notification: %v", err)
+	}
+}
+
+// Status returns a snapshot of the tracked validators and the latest results
+func (a *DutiesChecker) Status() domain.TrackerStatus {
+	a.statusMu.RLock()
+	defer a.statusMu.RUnlock()
+	return a.status
+}
+
+// EpochResult returns the results of a checked epoch. Only the latest epoch is available if there is no Store.
+func (a *DutiesChecker) EpochResult(epoch domain.Epoch) (domain.EpochResult, bool, error) {
+	if a.Store != nil {
+		return a.Store.GetEpochResult(epoch)
+	}
+	status := a.Status()
+	if status.LastResult != nil && status.LastResult.Epoch == epoch {
+		return *status.LastResult, true, nil
+	}
+	return domain.EpochResult{}, false, nil
+}
+
+func (a *DutiesChecker) updateStatus(update func(s *domain.TrackerStatus)) {
+	a.statusMu.Lock()
+	defer a.statusMu.Unlock()
+	update(&a.status)
+}
+
+// processEpochs checks every epoch since the last processed one up to the justified epoch, in order.
+// It stops at the first failing epoch so it is retried on the next run.
+func (a *DutiesChecker) processEpochs(ctx context.Context, justifiedEpoch domain.Epoch) error {
+	if a.lastProcessedEpoch >= justifiedEpoch {
+		logger.Debug("Justified epoch %d already processed, skipping check.", justifiedEpoch)
 		return nil
 	}
+	start := justifiedEpoch
+	if a.lastProcessedEpoch > 0 {
+		start = a.lastProcessedEpoch + 1
+	}
+	if uint64(justifiedEpoch-start) > a.MaxLookbackEpochs {
+		oldest := justifiedEpoch - domain.Epoch(a.MaxLookbackEpochs)
+		logger.Warn("Epochs %d to %d exceed the maximum lookback of %d epochs and will not be checked", start, oldest-1, a.MaxLookbackEpochs)
+		start = oldest
+	}
+	// Epochs re-evaluated after a reorg were not skipped, performChecks logs them
+	if start < justifiedEpoch && start > a.reevaluateUpTo {
+		logger.Info("Catching up on %d epochs skipped since epoch %d", justifiedEpoch-start, start)
+	}
+
+	for epoch := start; epoch <= justifiedEpoch; epoch++ {
+		mode := checkNew
+		switch {
+		case epoch <= a.reevaluateUpTo:
+			mode = checkReorg
+		case epoch < justifiedEpoch:
+			mode = checkBackfill
+		}
+		if err := a.performChecks(ctx, epoch, mode); err != nil {
+			return err
+		}
+		a.lastProcessedEpoch = epoch
+		metrics.SetLastProcessedEpoch(epoch)
+		a.saveState()
+	}
+	return nil
+}
 
-	indices, err := a.Beacon.GetValidatorIndicesByPubkeys(ctx, pubkeys)
+// LoadState restores the state persisted by a previous run, so a restart does not re-send notifications.
+// Must be called before Run.
+func (a *DutiesChecker) LoadState() error {
+	if a.Store == nil {
+		return nil
+	}
+	state, found, err := a.Store.LoadCheckerState()
 	if err != nil {
-		logger.Error("Error fetching validator indices from beacon node: %v", err)
 		return err
 	}
+	if !found {
+		logger.Info("No persisted checker state found, starting from scratch")
+		return nil
+	}
+
+	a.lastJustifiedEpoch = state.LastJustifiedEpoch
+	a.lastProcessedEpoch = state.LastProcessedEpoch
+	if state.LivenessStates != nil {
+		a.LivenessStates = state.LivenessStates
+	}
+	a.livenessIncidents = state.LivenessIncidents
+	if a.livenessIncidents == nil {
+		a.livenessIncidents = make(map[domain.ValidatorIndex]domain.ValidatorNotification)
+		for index, liveness := range a.LivenessStates {
+			if liveness.Offline {
+				a.livenessIncidents[index] = domain.Notifications.Liveness.ForValidator(index)
+			}
+		}
+	}
+	if state.SlashedNotified != nil {
+		a.SlashedNotified = state.SlashedNotified
+	}
+	a.syncCommitteeMembers = state.SyncCommitteeMembers
+	a.dailyRewards = state.DailyRewards
+	a.lifecycleStatuses = state.LifecycleStatuses
+	logger.Info("Restored checker state from justified epoch %d", state.LastJustifiedEpoch)
+	return nil
+}
+
+// saveState persists the checker state. Errors are only logged, the checker keeps working in memory.
+func (a *DutiesChecker) saveState() {
+	if a.Store == nil {
+		return
+	}
+	state := domain.CheckerState{
+		LastJustifiedEpoch:   a.lastJustifiedEpoch,
+		LastProcessedEpoch:   a.lastProcessedEpoch,
+		SlashedNotified:      a.SlashedNotified,
+		LivenessStates:       a.LivenessStates,
+		LivenessIncidents:    a.livenessIncidents,
+		SyncCommitteeMembers: a.syncCommitteeMembers,
+		DailyRewards:         a.dailyRewards,
+		LifecycleStatuses:    a.lifecycleStatuses,
+	}
+	if err := a.Store.SaveCheckerState(state); err != nil {
+		logger.Warn("Error persisting checker state: %v", err)
+	}
+}
+
+// checkMode is why an epoch is checked
+type checkMode int
+
+const (
+	// checkNew is the first check of the justified epoch
+	checkNew checkMode = iota
+	// checkBackfill is the first check of an epoch skipped while the beacon node was unreachable
+	checkBackfill
+	// checkReorg checks again an epoch whose results a reorg made stale
+	checkReorg
+)
+
+// performChecks runs all the checks for an epoch
+func (a *DutiesChecker) performChecks(ctx context.Context, justifiedEpoch domain.Epoch, mode checkMode) error {
+	switch mode {
+	case checkBackfill:
+		logger.Info("Backfilling skipped epoch %d.", justifiedEpoch)
+	case checkReorg:
+		logger.Info("Re-evaluating epoch %d after a reorg.", justifiedEpoch)
+	default:
+		logger.Info("New justified epoch %d detected.", justifiedEpoch)
+	}
+	start := time.Now()
+	defer func() { metrics.ObserveCheckDuration(time.Since(start)) }()
+
+	// An epoch checked again after a reorg overwrites its result, but only notifies what the reorg changed
+	var reeval *reevaluation
+	if mode == checkReorg {
+		reeval = a.previousResult(justifiedEpoch)
+	}
+
+	notificationsEnabled, err := a.Dappmanager.GetNotificationsEnabled(ctx)
+	if err != nil {
+		logger.Warn("Error fetching notifications enabled, notification will not be sent: %v", err)
+	}
+
+	keys, err := a.Keys.GetValidatorKeys()
+	if err != nil {
+		logger.Error("Error fetching validators from the key source: %v", err)
+		return err
+	}
+	a.updateStatus(func(s *domain.TrackerStatus) { s.Pubkeys = keys.Pubkeys })
+
+	if keys.Empty() {
+		logger.Debug("No validators found in the key source for epoch %d, nothing to check.", justifiedEpoch)
+		return nil
+	}
+
+	// Validators given by index are tracked as is, the others are looked up by pubkey and keep its tag.
+	// Only the active ones have duties to check, the rest are followed through their lifecycle.
+	tracked := domain.ValidatorKeys{Indices: keys.Indices}
+	validatorTags := make(map[domain.ValidatorIndex]string)
+	var lifecycles map[string]domain.ValidatorLifecycle
+	if len(keys.Pubkeys) > 0 {
+		lifecycles, err = a.Resolver.Resolve(ctx, keys.Pubkeys, justifiedEpoch)
+		if err != nil {
+			logger.Error("Error fetching validator indices from beacon node: %v", err)
+			return err
+		}
+		var activeIndices []domain.ValidatorIndex
+		for pubkey, lifecycle := range lifecycles {
+			if lifecycle.Validator == nil {
+				continue
+			}
+			if tag := keys.Tags[pubkey]; tag != "" {
+				validatorTags[lifecycle.Validator.Index] = tag
+			}
+			if lifecycle.Status.Active() {
+				activeIndices = append(activeIndices, lifecycle.Validator.Index)
+			}
+		}
+		slices.Sort(activeIndices)
+		tracked.Merge(domain.ValidatorKeys{Indices: activeIndices})
+		if reeval == nil {
+			a.checkLifecycle(keys, lifecycles, notificationsEnabled)
+		}
+	}
+	indices := tracked.Indices
+	a.Tags.SetValidatorTags(validatorTags)
+	metrics.SetValidatorTags(indices, validatorTags)
+	metrics.SetValidatorStatuses(lifecycles)
+	a.updateStatus(func(s *domain.TrackerStatus) {
+		s.Indices = indices
+		s.Tags = validatorTags
+		s.Lifecycles = lifecycles
+	})
 	logger.Info("Found %d validator indices active", len(indices))
 
 	if len(indices) == 0 {
@@ -86,81 +575,513 @@ func (a *DutiesChecker) performChecks(ctx context.Context, justifiedEpoch domain
 	}
 
 	offline, online, allLive, err := a.checkLiveness(ctx, justifiedEpoch, indices)
+	livenessChecked := err == nil
 	if err != nil {
-		logger.Error("Error checking liveness for validators: %v", err)
-		return err
+		// Some beacon nodes only serve liveness for recent epochs, this must not block the catch-up
+		if mode != checkBackfill {
+			logger.Error("Error checking liveness for validators: %v", err)
+			return err
+		}
+		logger.Warn("Skipping liveness check for backfilled epoch %d: %v", justifiedEpoch, err)
+	}
+
+	result := domain.EpochResult{
+		Epoch:   justifiedEpoch,
+		Offline: offline,
+		Online:  online,
+	}
+	for _, index := range offline {
+		metrics.SetValidatorLive(index, false)
+	}
+	for _, index := range online {
+		metrics.SetValidatorLive(index, true)
+	}
+
+	// Rewards are computed before notifying, so the notifications can tell how much was missed
+	if a.Rewards != nil && !a.DisableRewards {
+		result.Rewards = a.checkRewards(ctx, justifiedEpoch, indices, reeval == nil)
 	}
 
 	// Debug print: show offline, online, and allLive status
 	logger.Debug("Liveness check: offline=%v, online=%v, allLive=%v", offline, online, allLive)
-	logger.Debug("Previously all live: %v, previously offline: %v", a.PreviouslyAllLive, a.PreviouslyOffline)
+	// The liveness state machines count epochs, so a re-evaluated epoch must not feed them again
+	if livenessChecked && reeval == nil {
+		_, recovered := a.updateLivenessStates(indices, offline, online)
 
-	// Check for the first condition: 1 or more validators offline when all were previously live
-	if len(offline) > 0 && a.PreviouslyAllLive {
+		// Each validator is its own incident, so new outages alert even if others are still offline. A maintenance
+		// window only holds the notification back: the validators it silenced stay offline without an incident, and
+		// are notified once it ends if they are still offline.
 		if notificationsEnabled[domain.Notifications.Liveness] {
-			logger.Debug("Sending notification for validators going offline: %v", offline)
-			if err := a.Notifier.SendValidatorLivenessNot(offline, justifiedEpoch, false); err != nil {
-				logger.Warn("Error sending validator liveness notification: %v", err)
+			if toNotify, _ := a.filterSilenced(domain.Notifications.Liveness, a.unnotifiedOffline()); len(toNotify) > 0 {
+				logger.Debug("Sending notification for validators going offline: %v", toNotify)
+				a.notifyOffline(toNotify, justifiedEpoch)
 			}
 		}
-		a.PreviouslyAllLive = false
-		a.PreviouslyOffline = true
+		// Recoveries are never silenced, they resolve incidents opened before the maintenance window
+		if len(recovered) > 0 && notificationsEnabled[domain.Notifications.Liveness] {
+			logger.Debug("Sending notification for validators back online: %v", recovered)
+			a.notifyRecovered(recovered, justifiedEpoch)
+		}
 	}
 
-	// Check for the second condition: all validators online after 1 or more were offline
-	if allLive && a.PreviouslyOffline {
-		if notificationsEnabled[domain.Notifications.Liveness] {
-			logger.Debug("Sending notification for all validators back online: %v", indices)
-			if err := a.Notifier.SendValidatorLivenessNot(indices, justifiedEpoch, true); err != nil {
-				logger.Warn("Error sending validator liveness notification: %v", err)
-			}
+	// Check attestation quality of the previous epoch, its inclusion window ends with the justified epoch
+	if a.Attestations != nil && !a.DisableAttestations && justifiedEpoch > 0 {
+		attestations, err := a.Attestations.CheckEpoch(ctx, justifiedEpoch-1, indices)
+		if err != nil {
+			logger.Warn("Error checking attestations for epoch %d: %v", justifiedEpoch-1, err)
 		}
-		a.PreviouslyAllLive = true
-		a.PreviouslyOffline = false
+		result.Attestations = attestations
 	}
 
 	// Check block proposals (successful or missed)
-	proposed, missed, err := a.checkProposals(ctx, justifiedEpoch, indices)
+	outcomes, proposals, err := a.checkProposals(ctx, justifiedEpoch, indices)
 	if err != nil {
 		logger.Error("Error checking block proposals: %v", err)
 		return err
 	}
-	if len(proposed) > 0 && notificationsEnabled[domain.Notifications.Proposal] {
-		if err := a.Notifier.SendBlockProposalNot(proposed, justifiedEpoch, true); err != nil {
-			logger.Warn("Error sending block proposal notification: %v", err)
+	result.Proposed = outcomes[domain.ProposalProposed]
+	result.MissedProposals = outcomes[domain.ProposalMissed]
+	result.OrphanedProposals = outcomes[domain.ProposalOrphaned]
+	result.Proposals = proposals
+	for _, outcome := range []domain.ProposalOutcome{domain.ProposalProposed, domain.ProposalOrphaned, domain.ProposalMissed} {
+		var validators []domain.ValidatorIndex
+		for _, index := range outcomes[outcome] {
+			if reeval.proposalChanged(index, outcome) {
+				metrics.IncValidatorProposal(index, outcome)
+				validators = append(validators, index)
+			}
 		}
-	}
-	if len(missed) > 0 && notificationsEnabled[domain.Notifications.Proposal] {
-		if err := a.Notifier.SendBlockProposalNot(missed, justifiedEpoch, false); err != nil {
+		if len(validators) == 0 || !notificationsEnabled[domain.Notifications.Proposal] {
+			continue
+		}
+		if validators, _ = a.filterSilenced(domain.Notifications.Proposal, validators); len(validators) == 0 {
+			continue
+		}
+		if err := a.notifyValidators(domain.Notifications.Proposal, validators, func(n ports.NotifierPort, validators []domain.ValidatorIndex) error {
+			var missedRewards map[domain.ValidatorIndex]domain.Gwei
+			if outcome != domain.ProposalProposed {
+				missedRewards = a.missedRewardsToday(validators)
+			}
+			// Only the details of the proposals of these validators, which share the tag and the outcome
+			var details []domain.ProposalResult
+			for _, p := range proposals {
+				if slices.Contains(validators, p.ValidatorIndex) {
+					details = append(details, p)
+				}
+			}
+			return n.SendBlockProposalNot(validators, justifiedEpoch, outcome, missedRewards, details)
+		}); err != nil {
 			logger.Warn("Error sending block proposal notification: %v", err)
 		}
 	}
 
+	// Check sync committee participation
+	if a.SyncCommittee != nil && !a.DisableSyncCommittee {
+		result.SyncCommittee = a.checkSyncCommittee(ctx, justifiedEpoch, indices, notificationsEnabled, reeval)
+	}
+
 	// Check for slashed validators
 	slashed, err := a.Beacon.GetSlashedValidators(ctx, indices)
 	if err != nil {
 		logger.Error("Error fetching slashed validators: %v", err)
 		return err
 	}
+	result.Slashed = slashed
+	slashedSet := make(map[domain.ValidatorIndex]bool, len(slashed))
+	for _, index := range slashed {
+		slashedSet[index] = true
+	}
+	for _, index := range indices {
+		metrics.SetValidatorSlashed(index, slashedSet[index])
+	}
 
 	// Notify about slashed validators only if they haven't been notified before
 	var toNotify []domain.ValidatorIndex
 	for _, index := range slashed {
 		if !a.SlashedNotified[index] {
 			toNotify = append(toNotify, index)
-			a.SlashedNotified[index] = true
 		}
 	}
+	// Silenced validators are not marked as notified, so they are alerted once the maintenance window ends
+	if len(toNotify) > 0 && notificationsEnabled[domain.Notifications.Slashed] {
+		toNotify, _ = a.filterSilenced(domain.Notifications.Slashed, toNotify)
+	}
+	for _, index := range toNotify {
+		a.SlashedNotified[index] = true
+	}
 
 	if len(toNotify) > 0 && notificationsEnabled[domain.Notifications.Slashed] {
-		if err := a.Notifier.SendValidatorsSlashedNot(toNotify, justifiedEpoch); err != nil {
+		if err := a.notifyValidators(domain.Notifications.Slashed, toNotify, func(n ports.NotifierPort, validators []domain.ValidatorIndex) error {
+			return n.SendValidatorsSlashedNot(validators, justifiedEpoch)
+		}); err != nil {
 			logger.Warn("Error sending validator slashed notification: %v", err)
 		}
 	}
 
+	a.updateStatus(func(s *domain.TrackerStatus) {
+		s.LastResult = &result
+	})
+	if a.Store != nil {
+		if err := a.Store.SaveEpochResult(result); err != nil {
+			logger.Warn("Error persisting results for epoch %d: %v", justifiedEpoch, err)
+		}
+	}
+
 	return nil
 }
 
+// checkSyncCommittee notifies about validators newly selected for the sync committee and about low sync participation.
+// Errors are only logged, as sync committee results are not required by the rest of the checks.
+func (a *DutiesChecker) checkSyncCommittee(
+	ctx context.Context,
+	epochToTrack domain.Epoch,
+	indices []domain.ValidatorIndex,
+	notificationsEnabled domain.ValidatorNotificationsEnabled,
+	reeval *reevaluation,
+) []domain.SyncCommitteeResult {
+	results, err := a.SyncCommittee.CheckEpoch(ctx, epochToTrack, indices)
+	if err != nil {
+		logger.Warn("Error checking sync committee participation for epoch %d: %v", epochToTrack, err)
+		return nil
+	}
+
+	members := make(map[domain.ValidatorIndex]bool, len(results))
+	var selected, lowParticipation []domain.ValidatorIndex
+	for _, r := range results {
+		members[r.ValidatorIndex] = true
+		if !a.syncCommitteeMembers[r.ValidatorIndex] {
+			selected = append(selected, r.ValidatorIndex)
+		}
+		if r.Participation() < a.SyncParticipationThreshold && reeval.newlyLowParticipation(r.ValidatorIndex, a.SyncParticipationThreshold) {
+			lowParticipation = append(lowParticipation, r.ValidatorIndex)
+		}
+	}
+	a.syncCommitteeMembers = members
+
+	if len(selected) > 0 && notificationsEnabled[domain.Notifications.SyncCommittee] {
+		if selected, _ = a.filterSilenced(domain.Notifications.SyncCommittee, selected); len(selected) > 0 {
+			if err := a.notifyValidators(domain.Notifications.SyncCommittee, selected, func(n ports.NotifierPort, validators []domain.ValidatorIndex) error {
+				return n.SendSyncCommitteeSelectedNot(validators, epochToTrack)
+			}); err != nil {
+				logger.Warn("Error sending sync committee notification: %v", err)
+			}
+		}
+	}
+	if len(lowParticipation) > 0 && notificationsEnabled[domain.Notifications.SyncParticipation] {
+		if lowParticipation, _ = a.filterSilenced(domain.Notifications.SyncParticipation, lowParticipation); len(lowParticipation) > 0 {
+			if err := a.notifyValidators(domain.Notifications.SyncParticipation, lowParticipation, func(n ports.NotifierPort, validators []domain.ValidatorIndex) error {
+				return n.SendSyncParticipationNot(validators, epochToTrack, a.SyncParticipationThreshold)
+			}); err != nil {
+				logger.Warn("Error sending sync participation notification: %v", err)
+			}
+		}
+	}
+	return results
+}
+
+// checkRewards computes the rewards of the epoch and, if accumulate is true, adds them to the daily totals, which
+// start over every UTC day. Errors are only logged, as rewards are not required by the rest of the checks.
+func (a *DutiesChecker) checkRewards(ctx context.Context, epochToTrack domain.Epoch, indices []domain.ValidatorIndex, accumulate bool) []domain.ValidatorRewards {
+	rewards, err := a.Rewards.CheckEpoch(ctx, epochToTrack, indices)
+	if err != nil {
+		logger.Warn("Error checking rewards for epoch %d: %v", epochToTrack, err)
+		return nil
+	}
+	if !accumulate {
+		return rewards
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+	if a.dailyRewards.Day != today {
+		if a.dailyRewards.Day != "" {
+			var missed domain.Gwei
+			for _, r := range a.dailyRewards.Validators {
+				missed += r.Missed()
+			}
+			logger.Info("Validators missed %.6f ETH of rewards on %s", missed.ETH(), a.dailyRewards.Day)
+		}
+		a.dailyRewards = domain.DailyRewards{Day: today}
+	}
+	a.dailyRewards.Add(rewards)
+	return rewards
+}
+
+// checkLifecycle notifies the validators that reached a new status in their lifecycle. Validators seen for the
+// first time are only notified while they wait for activation, so the active ones are not notified on startup.
+// Transitions are informative, so the ones silenced by a maintenance window are not sent once it ends.
+func (a *DutiesChecker) checkLifecycle(keys domain.ValidatorKeys, lifecycles map[string]domain.ValidatorLifecycle, notificationsEnabled domain.ValidatorNotificationsEnabled) {
+	if a.lifecycleStatuses == nil {
+		a.lifecycleStatuses = make(map[string]domain.LifecycleStatus)
+	}
+	// Pubkeys no longer tracked are dropped. The ones missing from lifecycles, because the pending deposits
+	// queue could not be read, keep their status so they are not notified again.
+	tracked := make(map[string]bool, len(keys.Pubkeys))
+	for _, pubkey := range keys.Pubkeys {
+		tracked[pubkey] = true
+	}
+	for pubkey := range a.lifecycleStatuses {
+		if !tracked[pubkey] {
+			delete(a.lifecycleStatuses, pubkey)
+		}
+	}
+
+	reached := make(map[domain.LifecycleStatus][]domain.ValidatorLifecycle)
+	for pubkey, lifecycle := range lifecycles {
+		previous, seen := a.lifecycleStatuses[pubkey]
+		a.lifecycleStatuses[pubkey] = lifecycle.Status
+		notification, ok := domain.Notifications.ForLifecycle(lifecycle.Status)
+		if !ok || (!seen && !lifecycle.Status.Pending()) {
+			continue
+		}
+		if previousNotification, _ := domain.Notifications.ForLifecycle(previous); seen && previousNotification == notification {
+			continue
+		}
+		logger.Info("Validator %s reached status %s", pubkey, lifecycle.Status)
+		reached[lifecycle.Status] = append(reached[lifecycle.Status], lifecycle)
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(reached)) {
+		notification, _ := domain.Notifications.ForLifecycle(status)
+		if !notificationsEnabled[notification] {
+			continue
+		}
+		byTag := make(map[string][]domain.ValidatorLifecycle)
+		for _, lifecycle := range reached[status] {
+			if a.lifecycleSilenced(notification, lifecycle) {
+				continue
+			}
+			tag := keys.Tags[lifecycle.Pubkey]
+			byTag[tag] = append(byTag[tag], lifecycle)
+		}
+		for tag, validators := range byTag {
+			notifier, ok := a.Tags.RouteTag(notification, tag)
+			if !ok {
+				continue
+			}
+			slices.SortFunc(validators, func(x, y domain.ValidatorLifecycle) int { return strings.Compare(x.Pubkey, y.Pubkey) })
+			if err := notifier.SendValidatorLifecycleNot(status, validators); err != nil {
+				logger.Warn("Error sending validator %s notification: %v", status, err)
+			}
+		}
+	}
+}
+
+// lifecycleSilenced returns true if a maintenance window silences a lifecycle notification about a validator.
+// Validators without index are only silenced by the windows that cover every validator.
+func (a *DutiesChecker) lifecycleSilenced(notification domain.ValidatorNotification, lifecycle domain.ValidatorLifecycle) bool {
+	if lifecycle.Validator == nil {
+		return a.silenced(notification)
+	}
+	_, silenced := a.filterSilenced(notification, []domain.ValidatorIndex{lifecycle.Validator.Index})
+	return len(silenced) > 0
+}
+
+// unnotifiedOffline returns the offline validators whose outage was not notified
+func (a *DutiesChecker) unnotifiedOffline() []domain.ValidatorIndex {
+	var validators []domain.ValidatorIndex
+	for index, state := range a.LivenessStates {
+		if _, notified := a.livenessIncidents[index]; state.Offline && !notified {
+			validators = append(validators, index)
+		}
+	}
+	slices.Sort(validators)
+	return validators
+}
+
+// maxLivenessNotifications is the number of validators of a tag going offline together that are notified one by one
+const maxLivenessNotifications = 10
+
+// notifyOffline opens an incident for the validators that went offline. Up to maxLivenessNotifications
+// validators of a tag each get their own incident, so each one is resolved separately. Beyond it, as when
+// a shared validator client goes down, a single incident lists them all.
+func (a *DutiesChecker) notifyOffline(validators []domain.ValidatorIndex, epoch domain.Epoch) {
+	if a.livenessIncidents == nil {
+		a.livenessIncidents = make(map[domain.ValidatorIndex]domain.ValidatorNotification)
+	}
+	missedRewards := a.missedRewardsToday(validators)
+	for _, group := range a.Tags.Route(domain.Notifications.Liveness, validators) {
+		if len(group.Validators) > maxLivenessNotifications {
+			incident := domain.Notifications.Liveness.ForGroup(epoch, group.Validators[0])
+			for _, index := range group.Validators {
+				a.livenessIncidents[index] = incident
+			}
+			a.sendLivenessNot(group.Notifier, incident, group.Validators, epoch, false, missedRewards)
+			continue
+		}
+		for _, index := range group.Validators {
+			incident := domain.Notifications.Liveness.ForValidator(index)
+			a.livenessIncidents[index] = incident
+			a.sendLivenessNot(group.Notifier, incident, []domain.ValidatorIndex{index}, epoch, false, missedRewards)
+		}
+	}
+}
+
+// notifyRecovered resolves the incidents of the validators back online. An incident of several validators
+// is resolved once all of them are back online. Validators whose outage was not notified have nothing to resolve.
+func (a *DutiesChecker) notifyRecovered(validators []domain.ValidatorIndex, epoch domain.Epoch) {
+	recovered := make(map[domain.ValidatorNotification][]domain.ValidatorIndex)
+	for _, index := range validators {
+		if incident, ok := a.livenessIncidents[index]; ok {
+			recovered[incident] = append(recovered[incident], index)
+			delete(a.livenessIncidents, index)
+		}
+	}
+	missedRewards := a.missedRewardsToday(validators)
+	for _, incident := range slices.Sorted(maps.Keys(recovered)) {
+		if remaining := slices.Collect(maps.Values(a.livenessIncidents)); slices.Contains(remaining, incident) {
+			logger.Info("Validators %v are back online, incident %s stays open for the validators still offline", recovered[incident], incident)
+			continue
+		}
+		for _, group := range a.Tags.Route(domain.Notifications.Liveness, recovered[incident]) {
+			a.sendLivenessNot(group.Notifier, incident, group.Validators, epoch, true, missedRewards)
+		}
+	}
+}
+
+// sendLivenessNot notifies validators going offline or back online in the background, as a mass outage
+// notifies many incidents and must not delay the checks
+func (a *DutiesChecker) sendLivenessNot(notifier ports.NotifierPort, incident domain.ValidatorNotification, validators []domain.ValidatorIndex, epoch domain.Epoch, live bool, missedRewards map[domain.ValidatorIndex]domain.Gwei) {
+	a.sendInBackground(string(incident), func() {
+		if err := notifier.SendValidatorLivenessNot(incident, validators, epoch, live, missedRewards); err != nil {
+			logger.Warn("Error sending validator liveness notification %s: %v", incident, err)
+		}
+	})
+}
+
+// backgroundQueueSize is the number of notifications waiting to be sent off the check path
+const backgroundQueueSize = 256
+
+// sendInBackground queues a notification to be sent after the ones queued before it. The checks never wait
+// for the queue: a notification that does not fit is dropped and logged.
+func (a *DutiesChecker) sendInBackground(description string, send func()) {
+	select {
+	case a.background <- send:
+	default:
+		logger.Warn("Notification queue is full, dropping notification %s", description)
+	}
+}
+
+// reevaluation is the result an epoch had before a reorg made it stale, so only what changed is notified again.
+// A nil reevaluation is a first check, which notifies everything.
+type reevaluation struct {
+	previous domain.EpochResult
+	// Without the previous result nothing is notified again
+	found bool
+}
+
+// previousResult returns the result of an epoch checked before, from the store or the last result
+func (a *DutiesChecker) previousResult(epoch domain.Epoch) *reevaluation {
+	if last := a.Status().LastResult; last != nil && last.Epoch == epoch {
+		return &reevaluation{previous: *last, found: true}
+	}
+	if a.Store == nil {
+		return &reevaluation{}
+	}
+	previous, found, err := a.Store.GetEpochResult(epoch)
+	if err != nil {
+		logger.Warn("Error reading the previous results of epoch %d, its notifications will not be sent again: %v", epoch, err)
+	}
+	return &reevaluation{previous: previous, found: found}
+}
+
+// proposalChanged returns true if the proposal of a validator had another outcome before the reorg
+func (r *reevaluation) proposalChanged(index domain.ValidatorIndex, outcome domain.ProposalOutcome) bool {
+	if r == nil {
+		return true
+	}
+	previous, ok := r.previous.ProposalOutcome(index)
+	return r.found && (!ok || previous != outcome)
+}
+
+// newlyLowParticipation returns true if the sync committee participation of a validator was not low before the reorg
+func (r *reevaluation) newlyLowParticipation(index domain.ValidatorIndex, threshold float64) bool {
+	if r == nil {
+		return true
+	}
+	for _, s := range r.previous.SyncCommittee {
+		if s.ValidatorIndex == index {
+			return s.Participation() >= threshold
+		}
+	}
+	// Compacted results no longer tell the participation
+	return r.found && !r.previous.Compacted
+}
+
+// filterSilenced splits the validators into the ones to notify and the ones in a maintenance window
+func (a *DutiesChecker) filterSilenced(notification domain.ValidatorNotification, validators []domain.ValidatorIndex) (notify, silenced []domain.ValidatorIndex) {
+	if a.Silencer == nil {
+		return validators, nil
+	}
+	return a.Silencer.Filter(notification, validators)
+}
+
+// notifyValidators sends a notification about validators through the notifier of each of their tags,
+// leaving out the tags that turned it off
+func (a *DutiesChecker) notifyValidators(notification domain.ValidatorNotification, validators []domain.ValidatorIndex, send func(n ports.NotifierPort, validators []domain.ValidatorIndex) error) error {
+	var errs []error
+	for _, group := range a.Tags.Route(notification, validators) {
+		if err := send(group.Notifier, group.Validators); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// silenced returns true if a maintenance window silences a notification that is not about validators
+func (a *DutiesChecker) silenced(notification domain.ValidatorNotification) bool {
+	return a.Silencer != nil && a.Silencer.Silenced(notification)
+}
+
+// missedRewardsToday returns the rewards the validators missed today, nil if rewards are not computed
+func (a *DutiesChecker) missedRewardsToday(validators []domain.ValidatorIndex) map[domain.ValidatorIndex]domain.Gwei {
+	if a.Rewards == nil || a.DisableRewards {
+		return nil
+	}
+	return a.dailyRewards.Missed(validators)
+}
+
+// updateLivenessStates feeds the liveness of the epoch to each validator state machine and returns the
+// validators that went offline or recovered. Validators no longer tracked are dropped.
+func (a *DutiesChecker) updateLivenessStates(indices, offline, online []domain.ValidatorIndex) (wentOffline, recovered []domain.ValidatorIndex) {
+	tracked := make(map[domain.ValidatorIndex]bool, len(indices))
+	for _, index := range indices {
+		tracked[index] = true
+	}
+	for index := range a.LivenessStates {
+		if !tracked[index] {
+			delete(a.LivenessStates, index)
+			delete(a.livenessIncidents, index)
+		}
+	}
+
+	update := func(index domain.ValidatorIndex, live bool) {
+		state, ok := a.LivenessStates[index]
+		if !ok {
+			state = &domain.LivenessState{}
+			a.LivenessStates[index] = state
+		}
+		if !state.Update(live, a.MissedEpochsToAlert, a.LiveEpochsToResolve) {
+			return
+		}
+		if state.Offline {
+			logger.Info("Validator %d is now considered offline", index)
+			wentOffline = append(wentOffline, index)
+		} else {
+			logger.Info("Validator %d is back online", index)
+			recovered = append(recovered, index)
+		}
+	}
+	for _, index := range offline {
+		update(index, false)
+	}
+	for _, index := range online {
+		update(index, true)
+	}
+	slices.Sort(wentOffline)
+	slices.Sort(recovered)
+	return wentOffline, recovered
+}
+
 func (a *DutiesChecker) checkLiveness(
 	ctx context.Context,
 	epochToTrack domain.Epoch,
@@ -195,7 +1116,7 @@ func (a *DutiesChecker) checkProposals(
 	ctx context.Context,
 	epochToTrack domain.Epoch,
 	indices []domain.ValidatorIndex,
-) (proposed []domain.ValidatorIndex, missed []domain.ValidatorIndex, err error) {
+) (outcomes map[domain.ProposalOutcome][]domain.ValidatorIndex, proposals []domain.ProposalResult, err error) {
 	proposerDuties, err := a.Beacon.GetProposerDuties(ctx, epochToTrack, indices)
 	if err != nil {
 		return nil, nil, err
@@ -206,19 +1127,53 @@ func (a *DutiesChecker) checkProposals(
 		return nil, nil, nil
 	}
 
+	outcomes = make(map[domain.ProposalOutcome][]domain.ValidatorIndex)
 	for _, duty := range proposerDuties {
-		didPropose, err := a.Beacon.DidProposeBlock(ctx, duty.Slot)
+		outcome, err := a.proposalOutcome(ctx, duty)
 		if err != nil {
 			logger.Warn("⚠️ Could not determine if block was proposed at slot %d: %v", duty.Slot, err)
 			continue
 		}
-		if didPropose {
-			proposed = append(proposed, duty.ValidatorIndex)
+		outcomes[outcome] = append(outcomes[outcome], duty.ValidatorIndex)
+		switch outcome {
+		case domain.ProposalProposed:
 			logger.Info("✅ Validator %d successfully proposed a block at slot %d", duty.ValidatorIndex, duty.Slot)
-		} else {
-			missed = append(missed, duty.ValidatorIndex)
+			if a.Proposals != nil && !a.DisableProposalDetails {
+				proposal, err := a.Proposals.Inspect(ctx, duty)
+				if err != nil {
+					logger.Warn("Error inspecting the block proposed at slot %d: %v", duty.Slot, err)
+				} else {
+					proposals = append(proposals, proposal)
+				}
+			}
+		case domain.ProposalOrphaned:
+			logger.Warn("⚠️ Validator %d proposed a block at slot %d but it is not on the canonical chain", duty.ValidatorIndex, duty.Slot)
+		default:
 			logger.Warn("❌ Validator %d was scheduled to propose at slot %d but did not", duty.ValidatorIndex, duty.Slot)
 		}
 	}
-	return proposed, missed, nil
+	return outcomes, proposals, nil
+}
+
+// proposalOutcome looks for a block of the duty's validator at its slot. Epochs are only checked once justified,
+// so a block that is canonical for the head is also canonical for the justified checkpoint.
+func (a *DutiesChecker) proposalOutcome(ctx context.Context, duty domain.ProposerDuty) (domain.ProposalOutcome, error) {
+	blocks, err := a.Beacon.GetSlotBlocks(ctx, duty.Slot)
+	if err != nil {
+		return "", err
+	}
+	outcome := domain.ProposalMissed
+	for _, block := range blocks {
+		if block.ProposerIndex != duty.ValidatorIndex {
+			if block.Canonical {
+				logger.Warn("Block at slot %d was proposed by validator %d instead of %d", duty.Slot, block.ProposerIndex, duty.ValidatorIndex)
+			}
+			continue
+		}
+		if block.Canonical {
+			return domain.ProposalProposed, nil
+		}
+		outcome = domain.ProposalOrphaned
+	}
+	return outcome, nil
 }
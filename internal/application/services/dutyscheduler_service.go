@@ -0,0 +1,194 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// SchedulerSettings are the settings of the duty scheduler that can be reloaded while it runs
+type SchedulerSettings struct {
+	RefreshInterval time.Duration
+	// A notification is sent this long before each proposal, 0 disables it
+	ProposalNoticeBefore time.Duration
+}
+
+// DutyScheduler looks ahead at the duties of our validators: block proposals in the current and next epoch
+// and sync committee membership in the next period. Operators use it to avoid maintenance right before a duty.
+type DutyScheduler struct {
+	Beacon ports.BeaconChainAdapter
+	// Routes the upcoming proposal notifications by the tag of the validator
+	Tags        *TagRouter
+	Dappmanager ports.DappManagerPort
+	// The validators to look ahead for are the ones the checker tracks
+	Checker *DutiesChecker
+	// Optional, notifications are never silenced if nil
+	Silencer *Silencer
+
+	SchedulerSettings
+	reload      pendingSettings[SchedulerSettings]
+	noticesSent map[domain.Slot]bool
+
+	// Exposed to the API, guarded by mu as it is read from other goroutines
+	mu       sync.RWMutex
+	forecast domain.DutyForecast
+}
+
+// Run refreshes the forecast every RefreshInterval until the context is done
+func (s *DutyScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.RefreshInterval)
+	defer ticker.Stop()
+
+	s.refresh(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			if settings, ok := s.reload.take(); ok {
+				s.SchedulerSettings = settings
+				ticker.Reset(s.RefreshInterval)
+				logger.Info("Duty scheduler settings reloaded")
+			}
+			s.refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Reload replaces the settings of the scheduler, they are applied before its next refresh
+func (s *DutyScheduler) Reload(settings SchedulerSettings) {
+	s.reload.set(settings)
+}
+
+// Forecast returns the latest forecast of upcoming duties
+func (s *DutyScheduler) Forecast() domain.DutyForecast {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.forecast
+}
+
+func (s *DutyScheduler) refresh(ctx context.Context) {
+	indices := s.Checker.Status().Indices
+	if len(indices) == 0 {
+		logger.Debug("No validator indices known yet, skipping duty forecast.")
+		return
+	}
+
+	spec, err := s.Beacon.GetChainSpec(ctx)
+	if err != nil {
+		logger.Warn("Error fetching chain spec, skipping duty forecast: %v", err)
+		return
+	}
+	timing := spec.ChainTiming
+	now := time.Now()
+	currentSlot := timing.SlotAt(now)
+	currentEpoch := timing.EpochOf(currentSlot)
+
+	forecast := domain.DutyForecast{
+		UpdatedAt:    now,
+		CurrentSlot:  currentSlot,
+		CurrentEpoch: currentEpoch,
+	}
+
+	// Beacon nodes may not know the proposers of the next epoch yet, that is not an error worth more than a debug log
+	for _, epoch := range []domain.Epoch{currentEpoch, currentEpoch + 1} {
+		duties, err := s.Beacon.GetProposerDuties(ctx, epoch, indices)
+		if err != nil {
+			if epoch == currentEpoch {
+				logger.Warn("Error fetching proposer duties for epoch %d: %v", epoch, err)
+			} else {
+				logger.Debug("Proposer duties for next epoch %d not available: %v", epoch, err)
+			}
+			continue
+		}
+		for _, duty := range duties {
+			if duty.Slot < currentSlot {
+				continue
+			}
+			forecast.Proposals = append(forecast.Proposals, domain.UpcomingProposal{
+				ValidatorIndex: duty.ValidatorIndex,
+				Slot:           duty.Slot,
+				Epoch:          epoch,
+				Time:           timing.SlotTime(duty.Slot),
+			})
+		}
+	}
+	slices.SortFunc(forecast.Proposals, func(a, b domain.UpcomingProposal) int { return cmp.Compare(a.Slot, b.Slot) })
+
+	nextPeriod := timing.SyncCommitteePeriod(currentEpoch) + 1
+	nextPeriodStart := domain.Epoch(nextPeriod * timing.EpochsPerSyncCommitteePeriod)
+	syncDuties, err := s.Beacon.GetSyncCommitteeDuties(ctx, nextPeriodStart, indices)
+	if err != nil {
+		logger.Debug("Sync committee duties for period %d not available: %v", nextPeriod, err)
+	}
+	for _, duty := range syncDuties {
+		forecast.SyncCommittees = append(forecast.SyncCommittees, domain.UpcomingSyncCommittee{
+			ValidatorIndex: duty.ValidatorIndex,
+			Period:         nextPeriod,
+			StartEpoch:     nextPeriodStart,
+			EndEpoch:       nextPeriodStart + domain.Epoch(timing.EpochsPerSyncCommitteePeriod) - 1,
+			StartTime:      timing.SlotTime(timing.EpochStart(nextPeriodStart)),
+		})
+	}
+
+	s.mu.Lock()
+	s.forecast = forecast
+	s.mu.Unlock()
+
+	s.sendProposalNotices(ctx, forecast.Proposals, currentSlot, now)
+}
+
+// sendProposalNotices notifies once about each proposal starting within ProposalNoticeBefore
+func (s *DutyScheduler) sendProposalNotices(ctx context.Context, proposals []domain.UpcomingProposal, currentSlot domain.Slot, now time.Time) {
+	if s.ProposalNoticeBefore == 0 {
+		return
+	}
+	if s.noticesSent == nil {
+		s.noticesSent = make(map[domain.Slot]bool)
+	}
+
+	var due []domain.UpcomingProposal
+	for _, p := range proposals {
+		if !s.noticesSent[p.Slot] && p.Time.After(now) && p.Time.Sub(now) <= s.ProposalNoticeBefore {
+			due = append(due, p)
+		}
+	}
+	// Forget past proposals so the map does not grow forever
+	for slot := range s.noticesSent {
+		if slot < currentSlot {
+			delete(s.noticesSent, slot)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	notificationsEnabled, err := s.Dappmanager.GetNotificationsEnabled(ctx)
+	if err != nil {
+		logger.Warn("Error fetching notifications enabled, notification will not be sent: %v", err)
+		return
+	}
+	for _, p := range due {
+		logger.Info("Validator %d will propose at slot %d in %s", p.ValidatorIndex, p.Slot, p.Time.Sub(now).Round(time.Second))
+		s.noticesSent[p.Slot] = true
+		if !notificationsEnabled[domain.Notifications.UpcomingProposal] {
+			continue
+		}
+		if s.Silencer != nil {
+			if notify, _ := s.Silencer.Filter(domain.Notifications.UpcomingProposal, []domain.ValidatorIndex{p.ValidatorIndex}); len(notify) == 0 {
+				continue
+			}
+		}
+		for _, group := range s.Tags.Route(domain.Notifications.UpcomingProposal, []domain.ValidatorIndex{p.ValidatorIndex}) {
+			if err := group.Notifier.SendUpcomingProposalNot(p.ValidatorIndex, p.Slot, p.Time); err != nil {
+				logger.Warn("Error sending upcoming proposal notification: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+)
+
+// dependencyProbeTTL is how long the result of probing the dependencies is reused, so frequent readiness
+// probes do not load them
+const dependencyProbeTTL = 10 * time.Second
+
+// HealthChecker reports whether the tracker and the services it depends on are working,
+// so container orchestration can detect a stuck or blind tracker.
+type HealthChecker struct {
+	Beacon      ports.BeaconChainAdapter
+	Keys        ports.KeySource
+	Notifier    ports.NotifierPort
+	Dappmanager ports.DappManagerPort
+	Checker     *DutiesChecker
+
+	// The checker is considered stuck if it has not polled the justified epoch for longer than MaxCheckAge
+	MaxCheckAge time.Duration
+	StartedAt   time.Time
+
+	// Last probe of the dependencies, guarded by mu which also serializes the probes
+	mu         sync.Mutex
+	probedAt   time.Time
+	deps       []domain.DependencyHealth
+	beaconSync *domain.SyncStatus
+}
+
+// Live reports whether the checker is running, from its own state only
+func (h *HealthChecker) Live() domain.LivenessReport {
+	status := h.Checker.Status()
+	report := domain.LivenessReport{
+		LastCheckAt:        status.LastCheckAt,
+		LastCheckSucceeded: status.LastCheckSucceeded,
+	}
+	if !status.LastCheckAt.IsZero() {
+		report.LastCheckAgeSeconds = time.Since(status.LastCheckAt).Seconds()
+	}
+	lastActivity := status.LastPollAt
+	if lastActivity.IsZero() {
+		lastActivity = h.StartedAt
+	}
+	report.Live = time.Since(lastActivity) < h.MaxCheckAge
+	return report
+}
+
+// Check builds the readiness report, probing the dependencies at most once every dependencyProbeTTL
+func (h *HealthChecker) Check(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{LivenessReport: h.Live()}
+	report.Dependencies, report.BeaconSync = h.probeDependencies(ctx)
+
+	report.Ready = report.Live && (report.LastCheckAt.IsZero() || report.LastCheckSucceeded)
+	for _, dep := range report.Dependencies {
+		if !dep.Healthy {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// probeDependencies queries every dependency, or returns the result of the last probe if it is recent
+func (h *HealthChecker) probeDependencies(ctx context.Context) ([]domain.DependencyHealth, *domain.SyncStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if time.Since(h.probedAt) < dependencyProbeTTL {
+		return h.deps, h.beaconSync
+	}
+
+	var beaconSync *domain.SyncStatus
+	beaconHealth := domain.DependencyHealth{Name: "beacon", Healthy: true}
+	syncStatus, err := h.Beacon.GetSyncStatus(ctx)
+	if err != nil {
+		beaconHealth.Healthy = false
+		beaconHealth.Error = err.Error()
+	} else {
+		beaconSync = &syncStatus
+		if syncStatus.IsSyncing || syncStatus.IsOptimistic || syncStatus.ElOffline {
+			beaconHealth.Healthy = false
+			beaconHealth.Error = "beacon node is not synced"
+		}
+	}
+
+	keysHealth := domain.DependencyHealth{Name: "keys", Healthy: true}
+	if _, err := h.Keys.GetValidatorKeys(); err != nil {
+		keysHealth.Healthy = false
+		keysHealth.Error = err.Error()
+	}
+
+	dappmanagerHealth := domain.DependencyHealth{Name: "dappmanager", Healthy: true}
+	if _, err := h.Dappmanager.GetNotificationsEnabled(ctx); err != nil {
+		dappmanagerHealth.Healthy = false
+		dappmanagerHealth.Error = err.Error()
+	}
+
+	// Probing the notifier would send a notification, so rely on the last delivery instead
+	notifierHealth := domain.DependencyHealth{Name: "notifier", Healthy: true}
+	if err := h.Notifier.LastDeliveryError(); err != nil {
+		notifierHealth.Healthy = false
+		notifierHealth.Error = err.Error()
+	}
+
+	h.deps = []domain.DependencyHealth{beaconHealth, keysHealth, dappmanagerHealth, notifierHealth}
+	h.beaconSync = beaconSync
+	h.probedAt = time.Now()
+	return h.deps, h.beaconSync
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// activationDelayEpochs is how long a validator waits for activation once its deposit is processed, since
+// electra: an epoch to become eligible, two for its eligibility to be finalized and MAX_SEED_LOOKAHEAD+1
+const activationDelayEpochs = 8
+
+// IndexResolver resolves the pubkeys of the key source to their validators and follows each one from its
+// deposit to its full withdrawal. Indices never change once assigned, so the beacon node is only asked for
+// pubkeys it did not know yet and the indices are persisted. Statuses are refreshed every StatusRefreshInterval,
+// and on every call while a validator has an unknown status, waits for its activation to be scheduled or
+// reached the epoch of a scheduled transition. Pubkeys without index are looked up in the pending deposits
+// queue. Only used from the checker goroutine.
+type IndexResolver struct {
+	Beacon ports.BeaconChainAdapter
+	// Optional, indices are only kept in memory if nil
+	Store ports.StateStore
+
+	StatusRefreshInterval time.Duration
+
+	indices     map[string]domain.ValidatorIndex
+	states      map[domain.ValidatorIndex]domain.ValidatorState
+	lastRefresh time.Time
+	// Queue position of the pubkeys found in the pending deposits queue, and the pubkeys looked up there
+	deposits            map[string]domain.ActivationEstimate
+	depositsLookedUp    map[string]bool
+	lastDepositsRefresh time.Time
+	// Read on the first activation estimate
+	spec *domain.ChainSpec
+}
+
+// LoadIndices restores the indices persisted by a previous run. Must be called before Resolve.
+func (r *IndexResolver) LoadIndices() error {
+	if r.Store == nil {
+		return nil
+	}
+	indices, err := r.Store.LoadValidatorIndices()
+	if err != nil {
+		return err
+	}
+	r.indices = indices
+	return nil
+}
+
+// Resolve returns the lifecycle of each pubkey known to the beacon node, including the ones whose deposit
+// waits in the pending deposits queue. Pubkeys without index are asked for again on every call until they
+// get one. epoch is the current justified epoch.
+func (r *IndexResolver) Resolve(ctx context.Context, pubkeys []string, epoch domain.Epoch) (map[string]domain.ValidatorLifecycle, error) {
+	if r.indices == nil {
+		r.indices = make(map[string]domain.ValidatorIndex)
+	}
+
+	var unknown []string
+	for _, pubkey := range pubkeys {
+		if _, ok := r.indices[pubkey]; !ok {
+			unknown = append(unknown, pubkey)
+		}
+	}
+	if len(unknown) > 0 {
+		found, err := r.Beacon.GetValidatorIndicesByPubkeys(ctx, unknown)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			logger.Info("Resolved the indices of %d new validators, %d pubkeys are not known to the beacon node yet", len(found), len(unknown)-len(found))
+			maps.Copy(r.indices, found)
+			r.save()
+		}
+	}
+
+	var indices []domain.ValidatorIndex
+	var unresolved []string
+	for _, pubkey := range pubkeys {
+		if index, ok := r.indices[pubkey]; ok {
+			indices = append(indices, index)
+		} else {
+			unresolved = append(unresolved, pubkey)
+		}
+	}
+	if err := r.refreshStatus(ctx, indices, epoch); err != nil {
+		// Until the first refresh succeeds nothing is known to be active, later ones keep the previous statuses
+		if r.lastRefresh.IsZero() {
+			return nil, err
+		}
+		logger.Warn("Error refreshing the status of the validators, using the statuses of %s: %v", r.lastRefresh.Format(time.RFC3339), err)
+	}
+	// The queue position is only informative, so its errors do not stop the checks
+	if err := r.refreshDeposits(ctx, unresolved, epoch); err != nil {
+		logger.Warn("Error reading the pending deposits queue: %v", err)
+	}
+
+	lifecycles := make(map[string]domain.ValidatorLifecycle, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		if index, ok := r.indices[pubkey]; ok {
+			state, ok := r.states[index]
+			if !ok {
+				continue
+			}
+			lifecycles[pubkey] = domain.ValidatorLifecycle{
+				Pubkey:     pubkey,
+				Status:     state.Status,
+				Validator:  &state,
+				Activation: r.activationEstimate(ctx, state, epoch),
+			}
+		} else if estimate, ok := r.deposits[pubkey]; ok {
+			lifecycles[pubkey] = domain.ValidatorLifecycle{
+				Pubkey:     pubkey,
+				Status:     domain.LifecycleDeposited,
+				Activation: &estimate,
+			}
+		}
+	}
+	return lifecycles, nil
+}
+
+// refreshStatus reads the status of the validators if StatusRefreshInterval elapsed or any of them may have
+// changed status since the last refresh
+func (r *IndexResolver) refreshStatus(ctx context.Context, indices []domain.ValidatorIndex, epoch domain.Epoch) error {
+	due := time.Since(r.lastRefresh) >= r.StatusRefreshInterval
+	for _, index := range indices {
+		state, known := r.states[index]
+		next, scheduled := state.NextTransition()
+		switch {
+		case !known:
+			due = true
+		case state.Status == domain.LifecyclePendingInitialized, state.Status == domain.LifecyclePendingQueued && !scheduled:
+			due = true
+		case scheduled && next <= epoch:
+			due = true
+		}
+	}
+	if !due {
+		return nil
+	}
+	states, err := r.Beacon.GetValidatorStates(ctx, indices)
+	if err != nil {
+		return err
+	}
+	r.states = states
+	r.lastRefresh = time.Now()
+	logger.Debug("Refreshed the status of %d validators", len(indices))
+	return nil
+}
+
+// refreshDeposits looks up the pubkeys without index in the pending deposits queue, if
+// StatusRefreshInterval elapsed or any of them was not looked up yet
+func (r *IndexResolver) refreshDeposits(ctx context.Context, pubkeys []string, epoch domain.Epoch) error {
+	due := time.Since(r.lastDepositsRefresh) >= r.StatusRefreshInterval
+	for _, pubkey := range pubkeys {
+		if !r.depositsLookedUp[pubkey] {
+			due = true
+		}
+	}
+	if len(pubkeys) == 0 || !due {
+		return nil
+	}
+	queue, err := r.Beacon.GetPendingDeposits(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.deposits = make(map[string]domain.ActivationEstimate)
+	r.depositsLookedUp = make(map[string]bool, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		r.depositsLookedUp[pubkey] = true
+	}
+	spec := r.chainSpec(ctx)
+	var ahead domain.Gwei
+	for i, deposit := range queue {
+		_, seen := r.deposits[deposit.Pubkey]
+		if r.depositsLookedUp[deposit.Pubkey] && !seen {
+			position := uint64(i) + 1
+			estimate := domain.ActivationEstimate{QueuePosition: position, QueueLength: uint64(len(queue))}
+			// Each epoch processes deposits up to a count and an amount, whichever is reached first
+			if spec != nil && spec.DepositChurnLimit > 0 && spec.MaxPendingDepositsPerEpoch > 0 {
+				epochs := max(ceilDiv(uint64(ahead+deposit.Amount), uint64(spec.DepositChurnLimit)), ceilDiv(position, spec.MaxPendingDepositsPerEpoch))
+				estimate.Epoch = epoch + domain.Epoch(epochs) + activationDelayEpochs
+				estimate.Time = spec.SlotTime(spec.EpochStart(estimate.Epoch))
+			}
+			r.deposits[deposit.Pubkey] = estimate
+		}
+		ahead += deposit.Amount
+	}
+	r.lastDepositsRefresh = time.Now()
+	logger.Debug("Found %d of %d pubkeys without index in the pending deposits queue of %d deposits", len(r.deposits), len(pubkeys), len(queue))
+	return nil
+}
+
+// activationEstimate returns when a pending validator is activated, nil if it is not pending
+func (r *IndexResolver) activationEstimate(ctx context.Context, state domain.ValidatorState, epoch domain.Epoch) *domain.ActivationEstimate {
+	var estimate domain.ActivationEstimate
+	switch {
+	case state.Status == domain.LifecyclePendingQueued && state.ActivationEpoch != domain.FarFutureEpoch:
+		estimate = domain.ActivationEstimate{Epoch: state.ActivationEpoch, Scheduled: true}
+	case state.Status == domain.LifecyclePendingQueued:
+		estimate.Epoch = max(state.ActivationEligibilityEpoch, epoch) + activationDelayEpochs - 1
+	case state.Status == domain.LifecyclePendingInitialized:
+		estimate.Epoch = epoch + activationDelayEpochs
+	default:
+		return nil
+	}
+	if spec := r.chainSpec(ctx); spec != nil {
+		estimate.Time = spec.SlotTime(spec.EpochStart(estimate.Epoch))
+	}
+	return &estimate
+}
+
+// chainSpec returns the chain spec of the beacon node, read once. nil if it could not be read.
+func (r *IndexResolver) chainSpec(ctx context.Context) *domain.ChainSpec {
+	if r.spec != nil {
+		return r.spec
+	}
+	spec, err := r.Beacon.GetChainSpec(ctx)
+	if err != nil {
+		logger.Warn("Error reading the chain spec, activations will not be estimated: %v", err)
+		return nil
+	}
+	r.spec = &spec
+	return r.spec
+}
+
+// save persists the indices. Errors are only logged, they are resolved again after a restart.
+func (r *IndexResolver) save() {
+	if r.Store == nil {
+		return
+	}
+	if err := r.Store.SaveValidatorIndices(r.indices); err != nil {
+		logger.Warn("Error persisting validator indices: %v", err)
+	}
+}
+
+// ceilDiv divides rounding up
+func ceilDiv(a, b uint64) uint64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// ProposalChecker inspects the execution payload of the blocks our validators proposed: who got the block
+// value, how much it was and whether it came from an MEV relay.
+type ProposalChecker struct {
+	Beacon ports.BeaconChainAdapter
+	// Optional, MEV blocks are not detected if nil
+	Relays ports.RelayAdapter
+	// Optional, the value of locally built blocks is unknown if nil
+	Execution ports.ExecutionAdapter
+
+	// Fee recipient expected for each validator, DefaultFeeRecipient for the rest. Not checked if none applies.
+	FeeRecipients       map[domain.ValidatorIndex]string
+	DefaultFeeRecipient string
+}
+
+// Inspect returns the execution layer details of a block proposed for a duty
+func (c *ProposalChecker) Inspect(ctx context.Context, duty domain.ProposerDuty) (domain.ProposalResult, error) {
+	result := domain.ProposalResult{ValidatorIndex: duty.ValidatorIndex, Slot: duty.Slot}
+
+	payload, found, err := c.Beacon.GetBlockExecutionPayload(ctx, duty.Slot)
+	if err != nil {
+		return result, err
+	}
+	if !found {
+		logger.Warn("No block found at slot %d to inspect", duty.Slot)
+		return result, nil
+	}
+	result.BlockNumber = payload.BlockNumber
+	result.BlockHash = payload.BlockHash
+	result.FeeRecipient = payload.FeeRecipient
+
+	if c.Relays != nil {
+		relayPayloads, err := c.Relays.GetDeliveredPayloads(ctx, duty.Slot)
+		if err != nil {
+			logger.Warn("Error fetching delivered payloads from relays for slot %d: %v", duty.Slot, err)
+		}
+		for _, p := range relayPayloads {
+			// Relays also report payloads of blocks that were not included, only the canonical one counts
+			if strings.EqualFold(p.BlockHash, payload.BlockHash) {
+				result.MevRelay = p.Relay
+				result.BuilderPubkey = p.BuilderPubkey
+				result.FeeRecipient = p.ProposerFeeRecipient
+				result.BlockValue = p.Value
+				break
+			}
+		}
+	}
+
+	if !result.IsMev() && c.Execution != nil {
+		value, err := c.Execution.GetBlockPriorityFees(ctx, payload.BlockHash)
+		if err != nil {
+			logger.Warn("Error fetching the value of block %s from the execution client: %v", payload.BlockHash, err)
+		} else {
+			result.BlockValue = value
+		}
+	}
+
+	result.ExpectedFeeRecipient = c.expectedFeeRecipient(duty.ValidatorIndex)
+	if result.ExpectedFeeRecipient != "" && !strings.EqualFold(result.ExpectedFeeRecipient, result.FeeRecipient) {
+		result.FeeRecipientMismatch = true
+		logger.Warn("⚠️ Block of validator %d at slot %d paid fee recipient %s instead of %s",
+			duty.ValidatorIndex, duty.Slot, result.FeeRecipient, result.ExpectedFeeRecipient)
+	}
+	return result, nil
+}
+
+func (c *ProposalChecker) expectedFeeRecipient(index domain.ValidatorIndex) string {
+	if feeRecipient, ok := c.FeeRecipients[index]; ok {
+		return feeRecipient
+	}
+	return c.DefaultFeeRecipient
+}
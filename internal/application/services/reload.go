@@ -0,0 +1,30 @@
+package services
+
+import "sync"
+
+// pendingSettings hands reloaded settings to the goroutine of a service, which applies them between runs
+// so its settings never change in the middle of one
+type pendingSettings[T any] struct {
+	mu       sync.Mutex
+	settings *T
+}
+
+// set replaces any settings not applied yet
+func (p *pendingSettings[T]) set(settings T) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.settings = &settings
+}
+
+// take returns the settings to apply, if any were set since the last call
+func (p *pendingSettings[T]) take() (T, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.settings == nil {
+		var zero T
+		return zero, false
+	}
+	settings := *p.settings
+	p.settings = nil
+	return settings, true
+}
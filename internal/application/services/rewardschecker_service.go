@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// RewardsChecker compares the rewards our validators earned with the ones they would have earned
+// performing all their duties, so the cost of an outage or a missed block can be reported.
+type RewardsChecker struct {
+	Beacon ports.BeaconChainAdapter
+
+	// Rewards of the last blocks read, averaged to estimate a missed block whose neighbours are all empty
+	recent []domain.Gwei
+}
+
+// recentBlockRewards is the number of block rewards kept to estimate a missed block
+const recentBlockRewards = 32
+
+// CheckEpoch returns the rewards of every validator for the given epoch. Attestation rewards are taken from the
+// previous epoch, as attestations can be included until the end of the next epoch. Block and sync committee
+// rewards are taken from the given epoch.
+func (c *RewardsChecker) CheckEpoch(
+	ctx context.Context,
+	epoch domain.Epoch,
+	indices []domain.ValidatorIndex,
+) ([]domain.ValidatorRewards, error) {
+	if len(indices) == 0 {
+		logger.Debug("Called CheckEpoch with no validator indices, nothing to check.")
+		return nil, nil
+	}
+
+	totals := make(map[domain.ValidatorIndex]*domain.ValidatorRewards, len(indices))
+	for _, index := range indices {
+		totals[index] = &domain.ValidatorRewards{ValidatorIndex: index}
+	}
+
+	if epoch > 0 {
+		attestationRewards, err := c.Beacon.GetAttestationRewards(ctx, epoch-1, indices)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range attestationRewards {
+			if total, ok := totals[r.ValidatorIndex]; ok {
+				total.Add(r)
+			}
+		}
+	}
+
+	slotsPerEpoch, err := c.Beacon.GetSlotsPerEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	firstSlot := domain.Slot(uint64(epoch) * slotsPerEpoch)
+	lastSlot := firstSlot + domain.Slot(slotsPerEpoch) - 1
+
+	if err := c.addProposalRewards(ctx, epoch, indices, slotsPerEpoch, totals); err != nil {
+		return nil, err
+	}
+	if err := c.addSyncCommitteeRewards(ctx, epoch, indices, firstSlot, lastSlot, totals); err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.ValidatorRewards, 0, len(indices))
+	for _, index := range indices {
+		r := *totals[index]
+		if r.Missed() > 0 {
+			logger.Warn("❌ Validator %d missed %d gwei of rewards in epoch %d", r.ValidatorIndex, r.Missed(), epoch)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// addProposalRewards adds the reward of each proposed block. A missed block earns nothing, and what it would
+// have earned is estimated from the blocks around it.
+func (c *RewardsChecker) addProposalRewards(
+	ctx context.Context,
+	epoch domain.Epoch,
+	indices []domain.ValidatorIndex,
+	slotsPerEpoch uint64,
+	totals map[domain.ValidatorIndex]*domain.ValidatorRewards,
+) error {
+	duties, err := c.Beacon.GetProposerDuties(ctx, epoch, indices)
+	if err != nil {
+		return err
+	}
+	for _, duty := range duties {
+		total, ok := totals[duty.ValidatorIndex]
+		if !ok {
+			continue
+		}
+		reward, found, err := c.Beacon.GetBlockReward(ctx, duty.Slot)
+		if err != nil {
+			return err
+		}
+		if found {
+			c.record(reward.Total)
+		}
+		if found && reward.ProposerIndex == duty.ValidatorIndex {
+			total.Add(domain.ValidatorRewards{Earned: reward.Total, Ideal: reward.Total})
+			continue
+		}
+		estimate, err := c.estimateBlockReward(ctx, duty.Slot, slotsPerEpoch)
+		if err != nil {
+			return err
+		}
+		total.Add(domain.ValidatorRewards{Ideal: estimate})
+	}
+	return nil
+}
+
+// estimateBlockReward returns what the missed block of a slot would have earned: the reward of the next block,
+// looking up to the end of the next epoch, else the average of the recent blocks, else the reward of the
+// previous block, looking back an epoch. It never estimates 0, an error is returned if all those slots are empty.
+func (c *RewardsChecker) estimateBlockReward(ctx context.Context, missed domain.Slot, slotsPerEpoch uint64) (domain.Gwei, error) {
+	lastSlot := domain.Slot((uint64(missed)/slotsPerEpoch+2)*slotsPerEpoch) - 1
+	for slot := missed + 1; slot <= lastSlot; slot++ {
+		reward, found, err := c.Beacon.GetBlockReward(ctx, slot)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			c.record(reward.Total)
+			return reward.Total, nil
+		}
+	}
+	if average, ok := c.average(); ok {
+		return average, nil
+	}
+	for slot := missed; slot > 0 && uint64(missed-slot) < slotsPerEpoch; slot-- {
+		reward, found, err := c.Beacon.GetBlockReward(ctx, slot-1)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			c.record(reward.Total)
+			return reward.Total, nil
+		}
+	}
+	return 0, fmt.Errorf("no block around slot %d to estimate the reward of its missed block", missed)
+}
+
+// record keeps the reward of a block for the average of the recent blocks
+func (c *RewardsChecker) record(reward domain.Gwei) {
+	if len(c.recent) == recentBlockRewards {
+		c.recent = c.recent[1:]
+	}
+	c.recent = append(c.recent, reward)
+}
+
+// average returns the average reward of the recent blocks, false if none was read yet
+func (c *RewardsChecker) average() (domain.Gwei, bool) {
+	if len(c.recent) == 0 {
+		return 0, false
+	}
+	var sum domain.Gwei
+	for _, reward := range c.recent {
+		sum += reward
+	}
+	return sum / domain.Gwei(len(c.recent)), true
+}
+
+// addSyncCommitteeRewards adds the sync committee rewards of every block of the epoch. A missed signature is
+// penalized with the reward it would have earned, so the ideal reward is the absolute value of the reward.
+func (c *RewardsChecker) addSyncCommitteeRewards(
+	ctx context.Context,
+	epoch domain.Epoch,
+	indices []domain.ValidatorIndex,
+	firstSlot, lastSlot domain.Slot,
+	totals map[domain.ValidatorIndex]*domain.ValidatorRewards,
+) error {
+	duties, err := c.Beacon.GetSyncCommitteeDuties(ctx, epoch, indices)
+	if err != nil {
+		return err
+	}
+	if len(duties) == 0 {
+		return nil
+	}
+	members := make([]domain.ValidatorIndex, len(duties))
+	for i, duty := range duties {
+		members[i] = duty.ValidatorIndex
+	}
+
+	for slot := firstSlot; slot <= lastSlot; slot++ {
+		rewards, found, err := c.Beacon.GetSyncCommitteeRewards(ctx, slot, members)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		for _, r := range rewards {
+			total, ok := totals[r.ValidatorIndex]
+			if !ok {
+				continue
+			}
+			ideal := r.Reward
+			if ideal < 0 {
+				ideal = -ideal
+			}
+			total.Add(domain.ValidatorRewards{Earned: r.Reward, Ideal: ideal})
+		}
+	}
+	return nil
+}
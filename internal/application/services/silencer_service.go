@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// Silencer holds the maintenance windows during which notifications are not sent, so a deliberately stopped
+// validator client does not raise alerts. Checks keep running and recording results meanwhile, and a summary
+// of the silenced notifications is sent when each window ends.
+type Silencer struct {
+	Notifier    ports.NotifierPort
+	Dappmanager ports.DappManagerPort
+	// Optional, windows are only kept in memory if nil
+	Store ports.StateStore
+
+	// Ended windows are summarized on the first check after their end
+	CheckInterval time.Duration
+
+	// Read and updated from the checker, scheduler and API goroutines, guarded by mu
+	mu       sync.Mutex
+	silences []domain.Silence
+}
+
+// LoadSilences restores the windows persisted by a previous run. Must be called before Add and Run.
+func (s *Silencer) LoadSilences() error {
+	if s.Store == nil {
+		return nil
+	}
+	silences, err := s.Store.LoadSilences()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.silences = silences
+	s.mu.Unlock()
+	return nil
+}
+
+// Run summarizes the windows that ended every CheckInterval until the context is done
+func (s *Silencer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.CheckInterval)
+	defer ticker.Stop()
+
+	s.endExpired(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			s.endExpired(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// List returns the windows that did not end yet, including the ones that did not start
+func (s *Silencer) List() []domain.Silence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot()
+}
+
+// Add validates and stores a window, starting now if it has no start. A window with the ID of an existing one
+// replaces it, keeping the notifications it already silenced and, if the new one has no start, its start.
+func (s *Silencer) Add(silence domain.Silence) (domain.Silence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	// Windows always have an ID, so a new one without it is never found
+	existing := s.indexOf(silence.ID)
+	if silence.StartsAt.IsZero() && existing >= 0 {
+		silence.StartsAt = s.silences[existing].StartsAt
+	}
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return domain.Silence{}, errors.New("the window must end after it starts")
+	}
+	if !silence.EndsAt.After(now) {
+		return domain.Silence{}, errors.New("the window already ended")
+	}
+	for _, notification := range silence.Notifications {
+		if !slices.Contains(domain.Notifications.All(), notification) {
+			return domain.Silence{}, fmt.Errorf("unknown notification %s", notification)
+		}
+	}
+	if silence.ID == "" {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return domain.Silence{}, fmt.Errorf("failed to generate window ID: %w", err)
+		}
+		silence.ID = hex.EncodeToString(id)
+	}
+	silence.Suppressed = nil
+
+	i := existing
+	if i >= 0 {
+		silence.Suppressed = s.silences[i].Suppressed
+		s.silences[i] = silence
+	} else {
+		i = len(s.silences)
+		s.silences = append(s.silences, silence)
+	}
+	s.save()
+	logger.Info("Notifications silenced from %s to %s (window %s)", silence.StartsAt.Format(time.RFC3339), silence.EndsAt.Format(time.RFC3339), silence.ID)
+	return s.snapshot()[i], nil
+}
+
+// Remove ends a window early and sends its summary. The boolean is false if no window has that ID.
+func (s *Silencer) Remove(ctx context.Context, id string) bool {
+	s.mu.Lock()
+	i := s.indexOf(id)
+	if i < 0 {
+		s.mu.Unlock()
+		return false
+	}
+	silence := s.silences[i]
+	s.silences = slices.Delete(s.silences, i, i+1)
+	s.save()
+	s.mu.Unlock()
+
+	s.sendSummary(ctx, silence)
+	return true
+}
+
+// Filter splits the validators into the ones to notify and the ones silenced by an open window,
+// recording the latter for the summary of the window
+func (s *Silencer) Filter(notification domain.ValidatorNotification, validators []domain.ValidatorIndex) (notify, silenced []domain.ValidatorIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, index := range validators {
+		covered := false
+		for _, silence := range s.silences {
+			if silence.ActiveAt(now) && silence.Covers(notification, index) {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			silenced = append(silenced, index)
+		} else {
+			notify = append(notify, index)
+		}
+	}
+	if len(silenced) == 0 {
+		return notify, nil
+	}
+
+	changed := false
+	for i := range s.silences {
+		if !s.silences[i].ActiveAt(now) {
+			continue
+		}
+		var covered []domain.ValidatorIndex
+		for _, index := range silenced {
+			if s.silences[i].Covers(notification, index) {
+				covered = append(covered, index)
+			}
+		}
+		if len(covered) > 0 && s.silences[i].RecordSuppressed(notification, covered) {
+			changed = true
+		}
+	}
+	if changed {
+		s.save()
+	}
+	logger.Info("Notification %s silenced for validators %v", notification, silenced)
+	return notify, silenced
+}
+
+// Silenced returns true if an open window silences a notification that is not about validators, recording it
+// for the summary of the window
+func (s *Silencer) Silenced(notification domain.ValidatorNotification) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	silenced, changed := false, false
+	for i := range s.silences {
+		if s.silences[i].ActiveAt(now) && s.silences[i].CoversAll(notification) {
+			silenced = true
+			if s.silences[i].RecordSuppressed(notification, nil) {
+				changed = true
+			}
+		}
+	}
+	if changed {
+		s.save()
+	}
+	if silenced {
+		logger.Info("Notification %s silenced", notification)
+	}
+	return silenced
+}
+
+// endExpired drops the windows that ended and sends their summaries
+func (s *Silencer) endExpired(ctx context.Context) {
+	now := time.Now()
+	s.mu.Lock()
+	var ended []domain.Silence
+	s.silences = slices.DeleteFunc(s.silences, func(silence domain.Silence) bool {
+		if now.Before(silence.EndsAt) {
+			return false
+		}
+		ended = append(ended, silence)
+		return true
+	})
+	if len(ended) > 0 {
+		s.save()
+	}
+	s.mu.Unlock()
+
+	for _, silence := range ended {
+		s.sendSummary(ctx, silence)
+	}
+}
+
+func (s *Silencer) sendSummary(ctx context.Context, silence domain.Silence) {
+	logger.Info("Maintenance window %s ended, silenced notifications: %v", silence.ID, silence.Suppressed)
+	notificationsEnabled, err := s.Dappmanager.GetNotificationsEnabled(ctx)
+	if err != nil {
+		logger.Warn("Error fetching notifications enabled, notification will not be sent: %v", err)
+		return
+	}
+	if !notificationsEnabled[domain.Notifications.MaintenanceWindow] {
+		return
+	}
+	if err := s.Notifier.SendMaintenanceEndedNot(silence); err != nil {
+		logger.Warn("Error sending maintenance window notification: %v", err)
+	}
+}
+
+// indexOf returns the position of the window with the ID, -1 if there is none. Must be called with the lock held.
+func (s *Silencer) indexOf(id string) int {
+	return slices.IndexFunc(s.silences, func(silence domain.Silence) bool { return silence.ID == id })
+}
+
+// save persists the windows. Errors are only logged, the windows are still applied in memory. Must be called with the lock held.
+func (s *Silencer) save() {
+	if s.Store == nil {
+		return
+	}
+	if err := s.Store.SaveSilences(s.snapshot()); err != nil {
+		logger.Warn("Error persisting maintenance windows: %v", err)
+	}
+}
+
+// snapshot deep copies the windows, so they can be read after the lock is released. Must be called with the lock held.
+func (s *Silencer) snapshot() []domain.Silence {
+	silences := slices.Clone(s.silences)
+	for i := range silences {
+		if silences[i].Suppressed == nil {
+			continue
+		}
+		suppressed := make(map[domain.ValidatorNotification][]domain.ValidatorIndex, len(silences[i].Suppressed))
+		for notification, validators := range silences[i].Suppressed {
+			suppressed[notification] = slices.Clone(validators)
+		}
+		silences[i].Suppressed = suppressed
+	}
+	return silences
+}
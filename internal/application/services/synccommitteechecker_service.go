@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// SyncCommitteeChecker counts the sync committee signatures our validators missed by
+// inspecting the sync aggregate of every block in an epoch.
+type SyncCommitteeChecker struct {
+	Beacon ports.BeaconChainAdapter
+}
+
+// CheckEpoch returns one result per validator that is part of the sync committee in the given epoch.
+// Empty slots are skipped, as there was no block to include the signatures in.
+func (c *SyncCommitteeChecker) CheckEpoch(
+	ctx context.Context,
+	epoch domain.Epoch,
+	indices []domain.ValidatorIndex,
+) ([]domain.SyncCommitteeResult, error) {
+	if len(indices) == 0 {
+		logger.Debug("Called CheckEpoch with no validator indices, nothing to check.")
+		return nil, nil
+	}
+
+	duties, err := c.Beacon.GetSyncCommitteeDuties(ctx, epoch, indices)
+	if err != nil {
+		return nil, err
+	}
+	if len(duties) == 0 {
+		logger.Debug("No sync committee duties for any validators in epoch %d", epoch)
+		return nil, nil
+	}
+
+	slotsPerEpoch, err := c.Beacon.GetSlotsPerEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.SyncCommitteeResult, len(duties))
+	for i, duty := range duties {
+		results[i].ValidatorIndex = duty.ValidatorIndex
+	}
+
+	firstSlot := domain.Slot(uint64(epoch) * slotsPerEpoch)
+	for slot := firstSlot; slot < firstSlot+domain.Slot(slotsPerEpoch); slot++ {
+		bits, found, err := c.Beacon.GetBlockSyncAggregate(ctx, slot)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		for i, duty := range duties {
+			// A validator may appear several times in the committee, each position is a separate signature
+			for _, position := range duty.ValidatorSyncCommitteeIndices {
+				results[i].Expected++
+				if !isBitSet(bits, position) {
+					results[i].Missed++
+				}
+			}
+		}
+	}
+
+	for _, r := range results {
+		if r.Missed > 0 {
+			logger.Warn("❌ Validator %d missed %d of %d sync committee signatures in epoch %d", r.ValidatorIndex, r.Missed, r.Expected, epoch)
+		} else {
+			logger.Info("✅ Validator %d included all %d sync committee signatures in epoch %d", r.ValidatorIndex, r.Expected, epoch)
+		}
+	}
+	return results, nil
+}
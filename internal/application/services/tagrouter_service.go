@@ -0,0 +1,109 @@
+package services
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/dappnode/validator-tracker/internal/application/ports"
+	"github.com/dappnode/validator-tracker/internal/logger"
+)
+
+// TagSettings are the notification settings of the validators with a tag
+type TagSettings struct {
+	// Optional, the notifications go to the default notifier if nil
+	Notifier ports.NotifierPort
+	// Turns off every notification about the validators of the tag
+	Disabled bool
+	// Turns off only these notifications
+	DisabledNotifications []domain.ValidatorNotification
+}
+
+// TagRouter routes the notifications about validators by the tag their key source gave them, such as solo
+// or lido, so the validators of each staking protocol can notify elsewhere or not at all
+type TagRouter struct {
+	// Notifier of the untagged validators and of the tags without their own
+	Notifier ports.NotifierPort
+	// Tags that are not listed notify through Notifier, labeled with their tag
+	Tags map[string]TagSettings
+
+	// Set by the checker and read from the scheduler goroutine, guarded by mu
+	mu            sync.Mutex
+	validatorTags map[domain.ValidatorIndex]string
+	notifiers     map[string]ports.NotifierPort
+}
+
+// TagGroup is the validators of a notification that share a tag
+type TagGroup struct {
+	Tag        string
+	Notifier   ports.NotifierPort
+	Validators []domain.ValidatorIndex
+}
+
+// SetValidatorTags replaces the tags of the tracked validators
+func (r *TagRouter) SetValidatorTags(tags map[domain.ValidatorIndex]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validatorTags = tags
+}
+
+// Route groups the validators of a notification by tag, leaving out the tags that turned it off
+func (r *TagRouter) Route(notification domain.ValidatorNotification, validators []domain.ValidatorIndex) []TagGroup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var groups []TagGroup
+	for _, index := range validators {
+		tag := r.validatorTags[index]
+		i := slices.IndexFunc(groups, func(g TagGroup) bool { return g.Tag == tag })
+		if i < 0 {
+			i = len(groups)
+			groups = append(groups, TagGroup{Tag: tag, Notifier: r.notifier(tag)})
+		}
+		groups[i].Validators = append(groups[i].Validators, index)
+	}
+	return slices.DeleteFunc(groups, func(g TagGroup) bool {
+		if r.disabled(notification, g.Tag) {
+			logger.Debug("Notification %s turned off for tag %s, skipping validators %v", notification, g.Tag, g.Validators)
+			return true
+		}
+		return false
+	})
+}
+
+// RouteTag returns the notifier of the validators with a tag, for the notifications about validators that
+// have no index yet. The boolean is false if the tag turned the notification off.
+func (r *TagRouter) RouteTag(notification domain.ValidatorNotification, tag string) (ports.NotifierPort, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disabled(notification, tag) {
+		logger.Debug("Notification %s turned off for tag %s", notification, tag)
+		return nil, false
+	}
+	return r.notifier(tag), true
+}
+
+// disabled returns true if the tag turned the notification off
+func (r *TagRouter) disabled(notification domain.ValidatorNotification, tag string) bool {
+	settings := r.Tags[tag]
+	return settings.Disabled || slices.Contains(settings.DisabledNotifications, notification)
+}
+
+// notifier returns the notifier of a tag, creating it on first use. Must be called with the lock held.
+func (r *TagRouter) notifier(tag string) ports.NotifierPort {
+	if tag == "" {
+		return r.Notifier
+	}
+	if notifier, ok := r.notifiers[tag]; ok {
+		return notifier
+	}
+	notifier := r.Notifier
+	if settings := r.Tags[tag]; settings.Notifier != nil {
+		notifier = settings.Notifier
+	}
+	notifier = notifier.ForTag(tag)
+	if r.notifiers == nil {
+		r.notifiers = make(map[string]ports.NotifierPort)
+	}
+	r.notifiers[tag] = notifier
+	return notifier
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the schema of the YAML config file. Every setting is optional: missing ones keep their
+// default and environment variables override them. Unknown settings are rejected to catch typos.
+type fileConfig struct {
+	Network       *string             `yaml:"network"`
+	CustomNetwork customNetworkConfig `yaml:"customNetwork"`
+	Endpoints     endpointsConfig     `yaml:"endpoints"`
+	Keys          keysConfig          `yaml:"keys"`
+	Server        serverConfig        `yaml:"server"`
+	Timeouts      timeoutsConfig      `yaml:"timeouts"`
+	Intervals     intervalsConfig     `yaml:"intervals"`
+	Checks        checksConfig        `yaml:"checks"`
+	Notifications notificationsConfig `yaml:"notifications"`
+	// Notification settings by Brain tag, such as lido
+	Tags map[string]tagConfig `yaml:"tags"`
+}
+
+// customNetworkConfig holds the settings of a network that is not known, only read if the network is custom
+type customNetworkConfig struct {
+	// Required, names the notifications and the default endpoints
+	Name        *string `yaml:"name"`
+	ExplorerUrl *string `yaml:"explorerUrl"`
+	// Defaults to web3signer-<name>.dnp.dappnode.eth
+	SignerDnpName *string `yaml:"signerDnpName"`
+	// Defaults to the name
+	Category *string `yaml:"category"`
+}
+
+type endpointsConfig struct {
+	// The first beacon node is preferred and the rest are used for failover
+	Beacon           []string `yaml:"beacon"`
+	BeaconCrossCheck *bool    `yaml:"beaconCrossCheck"`
+	Execution        *string  `yaml:"execution"`
+	MevRelays        []string `yaml:"mevRelays"`
+	Web3Signer       *string  `yaml:"web3signer"`
+	Dappmanager      *string  `yaml:"dappmanager"`
+	Notifier         *string  `yaml:"notifier"`
+	Brain            *string  `yaml:"brain"`
+}
+
+// keysConfig selects the validators to track. The validators of every configured source are merged.
+type keysConfig struct {
+	// brain, web3signer or none
+	Source *string `yaml:"source"`
+	// Whether the other source is used while the selected one is unreachable
+	Fallback *bool `yaml:"fallback"`
+	// Pubkeys or indices tracked in addition to the ones of the source
+	Validators []string `yaml:"validators"`
+	// File with one pubkey or index per line, or directory of keystores, read again when it changes
+	File *string `yaml:"file"`
+}
+
+type serverConfig struct {
+	MetricsAddress *string `yaml:"metricsAddress"`
+	ApiAddress     *string `yaml:"apiAddress"`
+	StateFile      *string `yaml:"stateFile"`
+}
+
+type timeoutsConfig struct {
+	Beacon      *time.Duration `yaml:"beacon"`
+	Brain       *time.Duration `yaml:"brain"`
+	Web3Signer  *time.Duration `yaml:"web3signer"`
+	Notifier    *time.Duration `yaml:"notifier"`
+	Execution   *time.Duration `yaml:"execution"`
+	Relay       *time.Duration `yaml:"relay"`
+	HealthCheck *time.Duration `yaml:"healthCheck"`
+}
+
+type intervalsConfig struct {
+	Poll         *time.Duration `yaml:"poll"`
+	DutyForecast *time.Duration `yaml:"dutyForecast"`
+	SilenceCheck *time.Duration `yaml:"silenceCheck"`
+	// Which tracked validators are active is refreshed this often, their indices are only resolved once
+	StatusRefresh *time.Duration `yaml:"statusRefresh"`
+	MaxCheckAge   *time.Duration `yaml:"maxCheckAge"`
+}
+
+type checksConfig struct {
+	Attestations               *bool                            `yaml:"attestations"`
+	SyncCommittee              *bool                            `yaml:"syncCommittee"`
+	Rewards                    *bool                            `yaml:"rewards"`
+	ProposalDetails            *bool                            `yaml:"proposalDetails"`
+	SyncParticipationThreshold *float64                         `yaml:"syncParticipationThreshold"`
+	MaxLookbackEpochs          *uint64                          `yaml:"maxLookbackEpochs"`
+	MinBeaconPeers             *uint64                          `yaml:"minBeaconPeers"`
+	FeeRecipient               *string                          `yaml:"feeRecipient"`
+	FeeRecipients              map[domain.ValidatorIndex]string `yaml:"feeRecipients"`
+}
+
+type notificationsConfig struct {
+	BeaconOutageAlertAfter *time.Duration `yaml:"beaconOutageAlertAfter"`
+	ProposalNoticeBefore   *time.Duration `yaml:"proposalNoticeBefore"`
+	LivenessMissedEpochs   *uint64        `yaml:"livenessMissedEpochs"`
+	LivenessLiveEpochs     *uint64        `yaml:"livenessLiveEpochs"`
+	Silence                *silenceConfig `yaml:"silence"`
+}
+
+type tagConfig struct {
+	// Sends the notifications of the tag to another notifier
+	NotifierUrl *string `yaml:"notifierUrl"`
+	Category    *string `yaml:"category"`
+	// Turns off every notification of the tag
+	Disabled *bool `yaml:"disabled"`
+	// Turns off only these notifications, named without the network prefix such as validator-liveness
+	DisabledNotifications []string `yaml:"disabledNotifications"`
+}
+
+// silenceConfig is a maintenance window from startup until Until. Notifications are named without the
+// network prefix, such as validator-liveness. Both lists silence everything if empty.
+type silenceConfig struct {
+	Until         time.Time               `yaml:"until"`
+	Notifications []string                `yaml:"notifications"`
+	Validators    []domain.ValidatorIndex `yaml:"validators"`
+	Reason        string                  `yaml:"reason"`
+}
+
+// unknownFieldRegex matches the yaml error of a setting missing in the schema, which names Go types
+var unknownFieldRegex = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// readConfigFile decodes the config file. A missing file is only an error if required is true.
+func readConfigFile(path string, required bool) (fileConfig, error) {
+	var file fileConfig
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return file, nil
+	}
+	if err != nil {
+		return file, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	// An empty file is valid, it just keeps every default
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			for i, msg := range typeErr.Errors {
+				typeErr.Errors[i] = unknownFieldRegex.ReplaceAllString(msg, "unknown setting $1")
+			}
+			return file, fmt.Errorf("invalid config file %s:\n  - %s", path, strings.Join(typeErr.Errors, "\n  - "))
+		}
+		return file, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// set overrides the setting with the value of the config file, if it has one
+func set[T any](setting *T, value *T) {
+	if value != nil {
+		*setting = *value
+	}
+}
@@ -1,92 +1,671 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dappnode/validator-tracker/internal/application/domain"
 	"github.com/dappnode/validator-tracker/internal/logger"
 )
 
 type Config struct {
-	BeaconEndpoint     string
+	ConfigFile         string
+	BeaconEndpoints    []string
+	BeaconCrossCheck   bool
+	ExecutionEndpoint  string
+	MevRelays          []string
 	Web3SignerEndpoint string
 	Network            string
-	SignerDnpName      string
-	BeaconchaUrl       string
-	DappmanagerUrl     string
-	NotifierUrl        string
-	BrainUrl           string
+	// True if the network is not a known one, its settings are then supplied by the config
+	CustomNetwork        bool
+	NotificationCategory string
+	SignerDnpName        string
+	BeaconchaUrl         string
+	DappmanagerUrl       string
+	NotifierUrl          string
+	BrainUrl             string
+	// Source of the validator keys to track, the other one is used while it is unreachable if KeySourceFallback
+	KeySource         string
+	KeySourceFallback bool
+	// Validators tracked in addition to the ones of KeySource, listed in the config or read from a file
+	StaticValidators domain.ValidatorKeys
+	ValidatorsFile   string
+	StateFile        string
+	MetricsAddress   string
+	ApiAddress       string
+
+	// Timeouts of the requests to each dependency
+	BeaconTimeout      time.Duration
+	BrainTimeout       time.Duration
+	Web3SignerTimeout  time.Duration
+	NotifierTimeout    time.Duration
+	ExecutionTimeout   time.Duration
+	RelayTimeout       time.Duration
+	HealthCheckTimeout time.Duration
+
+	PollInterval         time.Duration
+	DutyForecastInterval time.Duration
+	SilenceCheckInterval time.Duration
+	// Which tracked validators are active is refreshed this often, their indices are only resolved once
+	StatusRefreshInterval time.Duration
+	// The tracker is reported as not live if no check completed for this long
+	MaxCheckAge time.Duration
+
+	// Optional checks, liveness, proposals and slashings are always checked
+	CheckAttestations    bool
+	CheckSyncCommittee   bool
+	CheckRewards         bool
+	CheckProposalDetails bool
+
+	SyncParticipationThreshold float64
+	MaxLookbackEpochs          uint64
+	BeaconOutageAlertAfter     time.Duration
+	MinBeaconPeers             uint64
+	MissedEpochsToAlert        uint64
+	LiveEpochsToResolve        uint64
+	ProposalNoticeBefore       time.Duration
+
+	// Expected fee recipient of every validator, FeeRecipients overrides it per validator
+	DefaultFeeRecipient string
+	FeeRecipients       map[domain.ValidatorIndex]string
+
+	// Maintenance window opened at startup, nil if none
+	Silence *domain.Silence
+
+	// Notification settings of the validators by the tag of their key source, such as lido
+	Tags map[string]TagConfig
 }
 
+// TagConfig are the notification settings of the validators with a tag. Empty fields keep the defaults.
+type TagConfig struct {
+	NotifierUrl           string
+	Category              string
+	Disabled              bool
+	DisabledNotifications []domain.ValidatorNotification
+}
+
+var executionAddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// Sources of the validator keys. The Web3Signer is only reachable from whitelisted hosts, the Brain is not restricted.
+// With none, only the validators listed in the config or in the validators file are tracked.
+const (
+	KeySourceBrain      = "brain"
+	KeySourceWeb3Signer = "web3signer"
+	KeySourceNone       = "none"
+)
+
+var networks = []string{"hoodi", "holesky", "mainnet", "gnosis", "lukso"}
+
+// customNetwork selects a network that is not known, such as a devnet, whose name and related settings are
+// supplied by the config and whose chain parameters are discovered from the beacon node
+const customNetwork = "custom"
+
+// The name of a custom network is part of hostnames and notification correlation IDs
+var networkNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// defaultConfigFile is only read if it exists, a file set with CONFIG_FILE must exist
+const defaultConfigFile = "/app/data/config.yaml"
+
+// LoadConfig loads the config, exiting with every invalid setting listed if it is not valid
 func LoadConfig() Config {
-	network := os.Getenv("NETWORK")
-	if network == "" {
-		network = "hoodi" // default
+	cfg, err := Load()
+	if err != nil {
+		logger.Fatal("%v", err)
 	}
+	return cfg
+}
 
-	// Build the dynamic endpoints
-	beaconEndpoint := fmt.Sprintf("http://beacon-chain.%s.dncore.dappnode:3500", network)
-	web3SignerEndpoint := fmt.Sprintf("http://web3signer.%s.dncore.dappnode:9000", network)
-	dappmanagerEndpoint := "http://dappmanager.dappnode"
-	notifierEndpoint := "http://notifier.notifications.dappnode:8080"
-	brainEndpoint := fmt.Sprintf("http://brain.web3signer-%s.dappnode", network)
-
-	// Allow override via environment variables
-	if envBeacon := os.Getenv("BEACON_ENDPOINT"); envBeacon != "" {
-		beaconEndpoint = envBeacon
+// Load builds the config from the defaults, the config file and the environment variables, in increasing
+// order of precedence. The error lists every invalid setting.
+func Load() (Config, error) {
+	configFile := defaultConfigFile
+	envConfigFile := os.Getenv("CONFIG_FILE")
+	if envConfigFile != "" {
+		configFile = envConfigFile
 	}
-	if envWeb3Signer := os.Getenv("WEB3SIGNER_ENDPOINT"); envWeb3Signer != "" {
-		web3SignerEndpoint = envWeb3Signer
+	file, err := readConfigFile(configFile, envConfigFile != "")
+	if err != nil {
+		return Config{}, err
 	}
-	if envDappmanager := os.Getenv("DAPPMANAGER_ENDPOINT"); envDappmanager != "" {
-		dappmanagerEndpoint = envDappmanager
+
+	// The network is resolved first, as most defaults depend on it
+	network := "hoodi"
+	set(&network, file.Network)
+	if envNetwork := os.Getenv("NETWORK"); envNetwork != "" {
+		network = envNetwork
 	}
-	if envNotifier := os.Getenv("NOTIFIER_URL"); envNotifier != "" {
-		notifierEndpoint = envNotifier
+	network = strings.ToLower(network)
+	custom := network == customNetwork
+	if custom {
+		network = ""
+		set(&network, file.CustomNetwork.Name)
+		if envName := os.Getenv("CUSTOM_NETWORK_NAME"); envName != "" {
+			network = envName
+		}
+		network = strings.ToLower(network)
+		if network == "" {
+			return Config{}, errors.New("invalid config: customNetwork.name (CUSTOM_NETWORK_NAME): the name of the custom network is required")
+		}
+		if slices.Contains(networks, network) {
+			return Config{}, fmt.Errorf("invalid config: customNetwork.name (CUSTOM_NETWORK_NAME): %s is a known network, set it as the network instead", network)
+		}
+		if !networkNameRegex.MatchString(network) {
+			return Config{}, fmt.Errorf("invalid config: customNetwork.name (CUSTOM_NETWORK_NAME): must only have lowercase letters, digits and hyphens, got %s", network)
+		}
+	} else if !slices.Contains(networks, network) {
+		return Config{}, fmt.Errorf("invalid config: unknown network %s, must be one of %s or %s", network, strings.Join(networks, ", "), customNetwork)
 	}
-	if envBrain := os.Getenv("BRAIN_URL"); envBrain != "" {
-		brainEndpoint = envBrain
+
+	cfg := defaultConfig(network)
+	cfg.CustomNetwork = custom
+	cfg.ConfigFile = configFile
+	var p problems
+	applyFile(&cfg, file, &p)
+	applyEnv(&cfg, &p)
+	validate(cfg, &p)
+	if err := p.err(); err != nil {
+		return Config{}, err
 	}
+	return cfg, nil
+}
 
-	// Normalize network name for logs
-	network = strings.ToLower(network)
-	if network != "hoodi" && network != "holesky" && network != "mainnet" && network != "gnosis" && network != "lukso" {
-		logger.Fatal("Unknown network: %s", network)
+func defaultConfig(network string) Config {
+	cfg := Config{
+		BeaconEndpoints:      []string{fmt.Sprintf("http://beacon-chain.%s.dncore.dappnode:3500", network)},
+		ExecutionEndpoint:    fmt.Sprintf("http://execution.%s.dncore.dappnode:8545", network),
+		Web3SignerEndpoint:   fmt.Sprintf("http://web3signer.%s.dncore.dappnode:9000", network),
+		Network:              network,
+		NotificationCategory: network,
+		SignerDnpName:        fmt.Sprintf("web3signer-%s.dnp.dappnode.eth", network),
+		DappmanagerUrl:       "http://dappmanager.dappnode",
+		NotifierUrl:          "http://notifier.notifications.dappnode:8080",
+		BrainUrl:             fmt.Sprintf("http://brain.web3signer-%s.dappnode", network),
+		KeySource:            KeySourceBrain,
+		KeySourceFallback:    true,
+		StateFile:            "/app/data/state.json",
+		MetricsAddress:       ":9090",
+		ApiAddress:           "127.0.0.1:8080",
+
+		BeaconTimeout:      20 * time.Second,
+		BrainTimeout:       3 * time.Second,
+		Web3SignerTimeout:  3 * time.Second,
+		NotifierTimeout:    3 * time.Second,
+		ExecutionTimeout:   10 * time.Second,
+		RelayTimeout:       5 * time.Second,
+		HealthCheckTimeout: 10 * time.Second,
+
+		PollInterval:          1 * time.Minute,
+		DutyForecastInterval:  1 * time.Minute,
+		SilenceCheckInterval:  1 * time.Minute,
+		StatusRefreshInterval: 15 * time.Minute,
+		MaxCheckAge:           15 * time.Minute,
+
+		CheckAttestations:    true,
+		CheckSyncCommittee:   true,
+		CheckRewards:         true,
+		CheckProposalDetails: true,
+
+		SyncParticipationThreshold: 80.0,
+		MaxLookbackEpochs:          100,
+		// 0 disables the beacon node outage notification
+		BeaconOutageAlertAfter: 30 * time.Minute,
+		// 0 disables the peer count check
+		MinBeaconPeers: 5,
+		// Consecutive missed epochs before a validator is alerted as offline, 1 alerts on the first miss
+		MissedEpochsToAlert: 1,
+		// Consecutive live epochs before an offline validator is considered back online
+		LiveEpochsToResolve: 1,
+		// 0 disables the upcoming proposal notification
+		ProposalNoticeBefore: 10 * time.Minute,
+
+		FeeRecipients: make(map[domain.ValidatorIndex]string),
+		Tags:          make(map[string]TagConfig),
 	}
 
-	var dnpName string
 	if network == "mainnet" {
-		dnpName = "web3signer.dnp.dappnode.eth"
-		brainEndpoint = "http://brain.web3signer.dappnode"
-	} else {
-		dnpName = fmt.Sprintf("web3signer-%s.dnp.dappnode.eth", network)
+		cfg.NotificationCategory = "ethereum"
+		cfg.SignerDnpName = "web3signer.dnp.dappnode.eth"
+		cfg.BrainUrl = "http://brain.web3signer.dappnode"
 	}
 
-	var beaconchaUrl string
+	// Custom networks have no explorer unless one is configured
 	switch network {
 	case "mainnet":
-		beaconchaUrl = "https://beaconcha.in"
+		cfg.BeaconchaUrl = "https://beaconcha.in"
 	case "holesky":
-		beaconchaUrl = "https://holesky.beaconcha.in"
+		cfg.BeaconchaUrl = "https://holesky.beaconcha.in"
 	case "hoodi":
-		beaconchaUrl = "https://hoodi.beaconcha.in"
+		cfg.BeaconchaUrl = "https://hoodi.beaconcha.in"
 	case "gnosis":
-		beaconchaUrl = "https://gnosischa.in"
+		cfg.BeaconchaUrl = "https://gnosischa.in"
 	case "lukso":
-		beaconchaUrl = "https://explorer.consensus.mainnet.lukso.network"
-	default:
-		logger.Fatal("Unsupported network for beaconcha URL: %s", network)
+		cfg.BeaconchaUrl = "https://explorer.consensus.mainnet.lukso.network"
+	}
+	return cfg
+}
+
+func applyFile(cfg *Config, file fileConfig, p *problems) {
+	if cfg.CustomNetwork {
+		set(&cfg.BeaconchaUrl, file.CustomNetwork.ExplorerUrl)
+		set(&cfg.SignerDnpName, file.CustomNetwork.SignerDnpName)
+		set(&cfg.NotificationCategory, file.CustomNetwork.Category)
+	} else if file.CustomNetwork != (customNetworkConfig{}) {
+		p.add("customNetwork: only applies if the network is %s", customNetwork)
+	}
+
+	if len(file.Endpoints.Beacon) > 0 {
+		cfg.BeaconEndpoints = file.Endpoints.Beacon
+	}
+	set(&cfg.BeaconCrossCheck, file.Endpoints.BeaconCrossCheck)
+	set(&cfg.ExecutionEndpoint, file.Endpoints.Execution)
+	if file.Endpoints.MevRelays != nil {
+		cfg.MevRelays = file.Endpoints.MevRelays
+	}
+	set(&cfg.Web3SignerEndpoint, file.Endpoints.Web3Signer)
+	set(&cfg.DappmanagerUrl, file.Endpoints.Dappmanager)
+	set(&cfg.NotifierUrl, file.Endpoints.Notifier)
+	set(&cfg.BrainUrl, file.Endpoints.Brain)
+
+	set(&cfg.KeySource, file.Keys.Source)
+	set(&cfg.KeySourceFallback, file.Keys.Fallback)
+	for _, entry := range file.Keys.Validators {
+		if err := cfg.StaticValidators.Add(entry); err != nil {
+			p.add("keys.validators: %v", err)
+		}
+	}
+	set(&cfg.ValidatorsFile, file.Keys.File)
+
+	set(&cfg.MetricsAddress, file.Server.MetricsAddress)
+	set(&cfg.ApiAddress, file.Server.ApiAddress)
+	set(&cfg.StateFile, file.Server.StateFile)
+
+	set(&cfg.BeaconTimeout, file.Timeouts.Beacon)
+	set(&cfg.BrainTimeout, file.Timeouts.Brain)
+	set(&cfg.Web3SignerTimeout, file.Timeouts.Web3Signer)
+	set(&cfg.NotifierTimeout, file.Timeouts.Notifier)
+	set(&cfg.ExecutionTimeout, file.Timeouts.Execution)
+	set(&cfg.RelayTimeout, file.Timeouts.Relay)
+	set(&cfg.HealthCheckTimeout, file.Timeouts.HealthCheck)
+
+	set(&cfg.PollInterval, file.Intervals.Poll)
+	set(&cfg.DutyForecastInterval, file.Intervals.DutyForecast)
+	set(&cfg.SilenceCheckInterval, file.Intervals.SilenceCheck)
+	set(&cfg.StatusRefreshInterval, file.Intervals.StatusRefresh)
+	set(&cfg.MaxCheckAge, file.Intervals.MaxCheckAge)
+
+	set(&cfg.CheckAttestations, file.Checks.Attestations)
+	set(&cfg.CheckSyncCommittee, file.Checks.SyncCommittee)
+	set(&cfg.CheckRewards, file.Checks.Rewards)
+	set(&cfg.CheckProposalDetails, file.Checks.ProposalDetails)
+	set(&cfg.SyncParticipationThreshold, file.Checks.SyncParticipationThreshold)
+	set(&cfg.MaxLookbackEpochs, file.Checks.MaxLookbackEpochs)
+	set(&cfg.MinBeaconPeers, file.Checks.MinBeaconPeers)
+	set(&cfg.DefaultFeeRecipient, file.Checks.FeeRecipient)
+	for index, address := range file.Checks.FeeRecipients {
+		cfg.FeeRecipients[index] = address
+	}
+
+	set(&cfg.BeaconOutageAlertAfter, file.Notifications.BeaconOutageAlertAfter)
+	set(&cfg.ProposalNoticeBefore, file.Notifications.ProposalNoticeBefore)
+	set(&cfg.MissedEpochsToAlert, file.Notifications.LivenessMissedEpochs)
+	set(&cfg.LiveEpochsToResolve, file.Notifications.LivenessLiveEpochs)
+	for tag, settings := range file.Tags {
+		tagCfg := TagConfig{}
+		set(&tagCfg.NotifierUrl, settings.NotifierUrl)
+		set(&tagCfg.Category, settings.Category)
+		set(&tagCfg.Disabled, settings.Disabled)
+		for _, name := range settings.DisabledNotifications {
+			notification, ok := domain.ParseNotification(cfg.Network, name)
+			if !ok {
+				p.add("tags.%s.disabledNotifications: unknown notification %s", tag, name)
+				continue
+			}
+			tagCfg.DisabledNotifications = append(tagCfg.DisabledNotifications, notification)
+		}
+		cfg.Tags[tag] = tagCfg
+	}
+	if silence := file.Notifications.Silence; silence != nil {
+		cfg.Silence = &domain.Silence{ID: "config", EndsAt: silence.Until, Validators: silence.Validators, Reason: silence.Reason}
+		for _, name := range silence.Notifications {
+			notification, ok := domain.ParseNotification(cfg.Network, name)
+			if !ok {
+				p.add("notifications.silence.notifications: unknown notification %s", name)
+				continue
+			}
+			cfg.Silence.Notifications = append(cfg.Silence.Notifications, notification)
+		}
+	}
+}
+
+// applyEnv overrides the settings with the environment variables. Only parse errors are reported here,
+// the values are validated along with the ones of the config file.
+func applyEnv(cfg *Config, p *problems) {
+	if cfg.CustomNetwork {
+		if envExplorer := os.Getenv("CUSTOM_NETWORK_EXPLORER_URL"); envExplorer != "" {
+			cfg.BeaconchaUrl = envExplorer
+		}
+		if envSigner := os.Getenv("CUSTOM_NETWORK_SIGNER_DNP_NAME"); envSigner != "" {
+			cfg.SignerDnpName = envSigner
+		}
+		if envCategory := os.Getenv("CUSTOM_NETWORK_CATEGORY"); envCategory != "" {
+			cfg.NotificationCategory = envCategory
+		}
+	}
+	if envBeacon := os.Getenv("BEACON_ENDPOINT"); envBeacon != "" {
+		// Comma-separated list, the first one is preferred and the rest are used for failover
+		cfg.BeaconEndpoints = nil
+		for _, endpoint := range strings.Split(envBeacon, ",") {
+			if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+				cfg.BeaconEndpoints = append(cfg.BeaconEndpoints, endpoint)
+			}
+		}
+	}
+	if envCrossCheck := os.Getenv("BEACON_CROSS_CHECK"); envCrossCheck != "" {
+		crossCheck, err := strconv.ParseBool(envCrossCheck)
+		if err != nil {
+			p.add("BEACON_CROSS_CHECK: must be true or false, got %s", envCrossCheck)
+		}
+		cfg.BeaconCrossCheck = crossCheck
+	}
+	if envExecution := os.Getenv("EXECUTION_ENDPOINT"); envExecution != "" {
+		cfg.ExecutionEndpoint = envExecution
 	}
+	// Comma-separated list of relay URLs, used to tell MEV blocks apart from locally built ones
+	if envRelays := os.Getenv("MEV_RELAYS"); envRelays != "" {
+		cfg.MevRelays = nil
+		for _, relay := range strings.Split(envRelays, ",") {
+			if relay = strings.TrimSpace(relay); relay != "" {
+				cfg.MevRelays = append(cfg.MevRelays, relay)
+			}
+		}
+	}
+	if envWeb3Signer := os.Getenv("WEB3SIGNER_ENDPOINT"); envWeb3Signer != "" {
+		cfg.Web3SignerEndpoint = envWeb3Signer
+	}
+	if envDappmanager := os.Getenv("DAPPMANAGER_ENDPOINT"); envDappmanager != "" {
+		cfg.DappmanagerUrl = envDappmanager
+	}
+	if envNotifier := os.Getenv("NOTIFIER_URL"); envNotifier != "" {
+		cfg.NotifierUrl = envNotifier
+	}
+	if envBrain := os.Getenv("BRAIN_URL"); envBrain != "" {
+		cfg.BrainUrl = envBrain
+	}
+	if envKeySource := os.Getenv("KEY_SOURCE"); envKeySource != "" {
+		cfg.KeySource = envKeySource
+	}
+	if envFallback := os.Getenv("KEY_SOURCE_FALLBACK"); envFallback != "" {
+		fallback, err := strconv.ParseBool(envFallback)
+		if err != nil {
+			p.add("KEY_SOURCE_FALLBACK: must be true or false, got %s", envFallback)
+		}
+		cfg.KeySourceFallback = fallback
+	}
+	// Comma-separated list of pubkeys or indices
+	if envValidators := os.Getenv("VALIDATORS"); envValidators != "" {
+		cfg.StaticValidators = domain.ValidatorKeys{}
+		for _, entry := range strings.Split(envValidators, ",") {
+			if strings.TrimSpace(entry) == "" {
+				continue
+			}
+			if err := cfg.StaticValidators.Add(entry); err != nil {
+				p.add("VALIDATORS: %v", err)
+			}
+		}
+	}
+	if envFile := os.Getenv("VALIDATORS_FILE"); envFile != "" {
+		cfg.ValidatorsFile = envFile
+	}
+	if envStateFile := os.Getenv("STATE_FILE"); envStateFile != "" {
+		cfg.StateFile = envStateFile
+	}
+	if envMetrics := os.Getenv("METRICS_ADDRESS"); envMetrics != "" {
+		cfg.MetricsAddress = envMetrics
+	}
+	if envApi := os.Getenv("API_ADDRESS"); envApi != "" {
+		cfg.ApiAddress = envApi
+	}
+
+	if envThreshold := os.Getenv("SYNC_PARTICIPATION_THRESHOLD"); envThreshold != "" {
+		threshold, err := strconv.ParseFloat(envThreshold, 64)
+		if err != nil {
+			p.add("SYNC_PARTICIPATION_THRESHOLD: must be a percentage between 0 and 100, got %s", envThreshold)
+		}
+		cfg.SyncParticipationThreshold = threshold
+	}
+	if envLookback := os.Getenv("MAX_LOOKBACK_EPOCHS"); envLookback != "" {
+		lookback, err := strconv.ParseUint(envLookback, 10, 64)
+		if err != nil {
+			p.add("MAX_LOOKBACK_EPOCHS: must be a positive integer, got %s", envLookback)
+		}
+		cfg.MaxLookbackEpochs = lookback
+	}
+	if envOutage := os.Getenv("BEACON_OUTAGE_ALERT_AFTER"); envOutage != "" {
+		outage, err := time.ParseDuration(envOutage)
+		if err != nil {
+			p.add("BEACON_OUTAGE_ALERT_AFTER: must be a duration such as 30m, got %s", envOutage)
+		}
+		cfg.BeaconOutageAlertAfter = outage
+	}
+	if envPeers := os.Getenv("MIN_BEACON_PEERS"); envPeers != "" {
+		peers, err := strconv.ParseUint(envPeers, 10, 64)
+		if err != nil {
+			p.add("MIN_BEACON_PEERS: must be a positive integer, got %s", envPeers)
+		}
+		cfg.MinBeaconPeers = peers
+	}
+	if envMissed := os.Getenv("LIVENESS_MISSED_EPOCHS"); envMissed != "" {
+		missed, err := strconv.ParseUint(envMissed, 10, 64)
+		if err != nil {
+			p.add("LIVENESS_MISSED_EPOCHS: must be an integer greater than 0, got %s", envMissed)
+		}
+		cfg.MissedEpochsToAlert = missed
+	}
+	if envLive := os.Getenv("LIVENESS_LIVE_EPOCHS"); envLive != "" {
+		live, err := strconv.ParseUint(envLive, 10, 64)
+		if err != nil {
+			p.add("LIVENESS_LIVE_EPOCHS: must be an integer greater than 0, got %s", envLive)
+		}
+		cfg.LiveEpochsToResolve = live
+	}
+	if envNotice := os.Getenv("PROPOSAL_NOTICE_BEFORE"); envNotice != "" {
+		notice, err := time.ParseDuration(envNotice)
+		if err != nil {
+			p.add("PROPOSAL_NOTICE_BEFORE: must be a duration such as 10m, got %s", envNotice)
+		}
+		cfg.ProposalNoticeBefore = notice
+	}
+
+	if envFeeRecipient := os.Getenv("FEE_RECIPIENT"); envFeeRecipient != "" {
+		cfg.DefaultFeeRecipient = envFeeRecipient
+	}
+	// Comma-separated list of index=address pairs
+	if envFeeRecipients := os.Getenv("FEE_RECIPIENTS"); envFeeRecipients != "" {
+		for _, pair := range strings.Split(envFeeRecipients, ",") {
+			index, address, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			parsedIndex, err := strconv.ParseUint(index, 10, 64)
+			if !ok || err != nil {
+				p.add("FEE_RECIPIENTS: entries must be index=address, got %s", pair)
+				continue
+			}
+			cfg.FeeRecipients[domain.ValidatorIndex(parsedIndex)] = address
+		}
+	}
+
+	// Comma-separated list of tags whose notifications are all turned off
+	for _, tag := range strings.Split(os.Getenv("DISABLED_TAGS"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tagCfg := cfg.Tags[tag]
+			tagCfg.Disabled = true
+			cfg.Tags[tag] = tagCfg
+		}
+	}
+
+	// Maintenance window from startup until SILENCE_UNTIL. Notifications are named without the network
+	// prefix, such as validator-liveness. Both lists are comma-separated and silence everything if empty.
+	if envUntil := os.Getenv("SILENCE_UNTIL"); envUntil != "" {
+		until, err := time.Parse(time.RFC3339, envUntil)
+		if err != nil {
+			p.add("SILENCE_UNTIL: must be a time such as 2025-01-02T15:04:05Z, got %s", envUntil)
+		}
+		cfg.Silence = &domain.Silence{ID: "env", EndsAt: until, Reason: os.Getenv("SILENCE_REASON")}
+		for _, name := range strings.Split(os.Getenv("SILENCE_NOTIFICATIONS"), ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			notification, ok := domain.ParseNotification(cfg.Network, name)
+			if !ok {
+				p.add("SILENCE_NOTIFICATIONS: unknown notification %s", name)
+				continue
+			}
+			cfg.Silence.Notifications = append(cfg.Silence.Notifications, notification)
+		}
+		for _, index := range strings.Split(os.Getenv("SILENCE_VALIDATORS"), ",") {
+			if index = strings.TrimSpace(index); index == "" {
+				continue
+			}
+			parsedIndex, err := strconv.ParseUint(index, 10, 64)
+			if err != nil {
+				p.add("SILENCE_VALIDATORS: must be validator indices, got %s", index)
+				continue
+			}
+			cfg.Silence.Validators = append(cfg.Silence.Validators, domain.ValidatorIndex(parsedIndex))
+		}
+	}
+}
+
+// validate checks the ranges of the settings. Each problem names the setting in the config file and,
+// if there is one, its environment variable.
+func validate(cfg Config, p *problems) {
+	if len(cfg.BeaconEndpoints) == 0 {
+		p.add("endpoints.beacon (BEACON_ENDPOINT): at least one beacon node is required")
+	}
+	if cfg.KeySource != KeySourceBrain && cfg.KeySource != KeySourceWeb3Signer && cfg.KeySource != KeySourceNone {
+		p.add("keys.source (KEY_SOURCE): must be %s, %s or %s, got %s", KeySourceBrain, KeySourceWeb3Signer, KeySourceNone, cfg.KeySource)
+	}
+	if cfg.KeySource == KeySourceNone && cfg.StaticValidators.Empty() && cfg.ValidatorsFile == "" {
+		p.add("keys.source (KEY_SOURCE): is %s but no validators are listed in keys.validators (VALIDATORS) nor keys.file (VALIDATORS_FILE)", KeySourceNone)
+	}
+	if cfg.SignerDnpName == "" {
+		p.add("customNetwork.signerDnpName (CUSTOM_NETWORK_SIGNER_DNP_NAME): the signer package name is required")
+	}
+	if cfg.NotificationCategory == "" {
+		p.add("customNetwork.category (CUSTOM_NETWORK_CATEGORY): the notification category is required")
+	}
+	for name, timeout := range map[string]time.Duration{
+		"timeouts.beacon":      cfg.BeaconTimeout,
+		"timeouts.brain":       cfg.BrainTimeout,
+		"timeouts.web3signer":  cfg.Web3SignerTimeout,
+		"timeouts.notifier":    cfg.NotifierTimeout,
+		"timeouts.execution":   cfg.ExecutionTimeout,
+		"timeouts.relay":       cfg.RelayTimeout,
+		"timeouts.healthCheck": cfg.HealthCheckTimeout,
+
+		"intervals.poll":          cfg.PollInterval,
+		"intervals.dutyForecast":  cfg.DutyForecastInterval,
+		"intervals.silenceCheck":  cfg.SilenceCheckInterval,
+		"intervals.statusRefresh": cfg.StatusRefreshInterval,
+		"intervals.maxCheckAge":   cfg.MaxCheckAge,
+	} {
+		if timeout <= 0 {
+			p.add("%s: must be a duration greater than 0, got %s", name, timeout)
+		}
+	}
+	if cfg.SyncParticipationThreshold < 0 || cfg.SyncParticipationThreshold > 100 {
+		p.add("checks.syncParticipationThreshold (SYNC_PARTICIPATION_THRESHOLD): must be a percentage between 0 and 100, got %g", cfg.SyncParticipationThreshold)
+	}
+	if cfg.BeaconOutageAlertAfter < 0 {
+		p.add("notifications.beaconOutageAlertAfter (BEACON_OUTAGE_ALERT_AFTER): must be a duration such as 30m, 0 disables it, got %s", cfg.BeaconOutageAlertAfter)
+	}
+	if cfg.ProposalNoticeBefore < 0 {
+		p.add("notifications.proposalNoticeBefore (PROPOSAL_NOTICE_BEFORE): must be a duration such as 10m, 0 disables it, got %s", cfg.ProposalNoticeBefore)
+	}
+	if cfg.MissedEpochsToAlert == 0 {
+		p.add("notifications.livenessMissedEpochs (LIVENESS_MISSED_EPOCHS): must be an integer greater than 0")
+	}
+	if cfg.LiveEpochsToResolve == 0 {
+		p.add("notifications.livenessLiveEpochs (LIVENESS_LIVE_EPOCHS): must be an integer greater than 0")
+	}
+	if cfg.DefaultFeeRecipient != "" && !executionAddressRegex.MatchString(cfg.DefaultFeeRecipient) {
+		p.add("checks.feeRecipient (FEE_RECIPIENT): must be an execution address, got %s", cfg.DefaultFeeRecipient)
+	}
+	for index, address := range cfg.FeeRecipients {
+		if !executionAddressRegex.MatchString(address) {
+			p.add("checks.feeRecipients (FEE_RECIPIENTS): must be an execution address for validator %d, got %s", index, address)
+		}
+	}
+	if cfg.Silence != nil && cfg.Silence.EndsAt.IsZero() {
+		p.add("notifications.silence.until (SILENCE_UNTIL): the end of the maintenance window is required")
+	}
+}
 
-	return Config{
-		BeaconEndpoint:     beaconEndpoint,
-		Web3SignerEndpoint: web3SignerEndpoint,
-		Network:            network,
-		SignerDnpName:      dnpName,
-		BeaconchaUrl:       beaconchaUrl,
-		DappmanagerUrl:     dappmanagerEndpoint,
-		NotifierUrl:        notifierEndpoint,
-		BrainUrl:           brainEndpoint,
+// problems collects every invalid setting, so they can all be fixed at once
+type problems []string
+
+func (p *problems) add(format string, args ...any) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	slices.Sort(p)
+	return errors.New("invalid config:\n  - " + strings.Join(p, "\n  - "))
+}
+
+// RestartRequired returns the settings that differ between the running config and a reloaded one but are
+// only applied on startup, as the adapters and servers using them are not recreated on reload
+func RestartRequired(running, reloaded Config) []string {
+	startupOnly := []struct {
+		name              string
+		running, reloaded any
+	}{
+		{"network", running.Network, reloaded.Network},
+		{"customNetwork.explorerUrl", running.BeaconchaUrl, reloaded.BeaconchaUrl},
+		{"customNetwork.signerDnpName", running.SignerDnpName, reloaded.SignerDnpName},
+		{"customNetwork.category", running.NotificationCategory, reloaded.NotificationCategory},
+		{"endpoints.beacon", running.BeaconEndpoints, reloaded.BeaconEndpoints},
+		{"endpoints.beaconCrossCheck", running.BeaconCrossCheck, reloaded.BeaconCrossCheck},
+		{"endpoints.execution", running.ExecutionEndpoint, reloaded.ExecutionEndpoint},
+		{"endpoints.mevRelays", running.MevRelays, reloaded.MevRelays},
+		{"endpoints.web3signer", running.Web3SignerEndpoint, reloaded.Web3SignerEndpoint},
+		{"endpoints.dappmanager", running.DappmanagerUrl, reloaded.DappmanagerUrl},
+		{"endpoints.notifier", running.NotifierUrl, reloaded.NotifierUrl},
+		{"endpoints.brain", running.BrainUrl, reloaded.BrainUrl},
+		{"keys.source", running.KeySource, reloaded.KeySource},
+		{"keys.fallback", running.KeySourceFallback, reloaded.KeySourceFallback},
+		{"keys.validators", running.StaticValidators, reloaded.StaticValidators},
+		{"keys.file", running.ValidatorsFile, reloaded.ValidatorsFile},
+		{"server.metricsAddress", running.MetricsAddress, reloaded.MetricsAddress},
+		{"server.apiAddress", running.ApiAddress, reloaded.ApiAddress},
+		{"server.stateFile", running.StateFile, reloaded.StateFile},
+		{"timeouts.beacon", running.BeaconTimeout, reloaded.BeaconTimeout},
+		{"timeouts.brain", running.BrainTimeout, reloaded.BrainTimeout},
+		{"timeouts.web3signer", running.Web3SignerTimeout, reloaded.Web3SignerTimeout},
+		{"timeouts.notifier", running.NotifierTimeout, reloaded.NotifierTimeout},
+		{"timeouts.execution", running.ExecutionTimeout, reloaded.ExecutionTimeout},
+		{"timeouts.relay", running.RelayTimeout, reloaded.RelayTimeout},
+		{"timeouts.healthCheck", running.HealthCheckTimeout, reloaded.HealthCheckTimeout},
+		{"intervals.silenceCheck", running.SilenceCheckInterval, reloaded.SilenceCheckInterval},
+		{"intervals.statusRefresh", running.StatusRefreshInterval, reloaded.StatusRefreshInterval},
+		{"intervals.maxCheckAge", running.MaxCheckAge, reloaded.MaxCheckAge},
+		{"checks.feeRecipient", running.DefaultFeeRecipient, reloaded.DefaultFeeRecipient},
+		{"checks.feeRecipients", running.FeeRecipients, reloaded.FeeRecipients},
+		{"tags", running.Tags, reloaded.Tags},
+	}
+	var changed []string
+	for _, setting := range startupOnly {
+		if !reflect.DeepEqual(setting.running, setting.reloaded) {
+			changed = append(changed, setting.name)
+		}
 	}
+	return changed
 }
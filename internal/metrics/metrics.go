@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dappnode/validator-tracker/internal/application/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "validator_tracker"
+
+// Components used to label the requests made to external services
+const (
+	ComponentBeacon      = "beacon"
+	ComponentBrain       = "brain"
+	ComponentWeb3Signer  = "web3signer"
+	ComponentDappmanager = "dappmanager"
+	ComponentNotifier    = "notifier"
+	ComponentRelay       = "relay"
+	ComponentExecution   = "execution"
+)
+
+var (
+	validatorLive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "validator_live",
+		Help:      "Whether the validator was seen attesting in the last checked epoch (1) or not (0).",
+	}, []string{"validator"})
+
+	validatorProposals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "validator_proposals_total",
+		Help:      "Block proposal duties of the validator by result (proposed, missed or orphaned).",
+	}, []string{"validator", "result"})
+
+	validatorSlashed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "validator_slashed",
+		Help:      "Whether the validator has been slashed (1) or not (0).",
+	}, []string{"validator"})
+
+	validatorInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "validator_info",
+		Help:      "Always 1 for each tracked validator, labeled with its tag (empty if untagged) to group the other metrics by staking protocol.",
+	}, []string{"validator", "tag"})
+
+	validatorStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "validator_status",
+		Help:      "Always 1 for each validator tracked by pubkey and known to the beacon chain, labeled with its lifecycle status such as pending_queued or active_ongoing.",
+	}, []string{"validator", "status"})
+
+	lastProcessedEpoch = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_processed_epoch",
+		Help:      "Last epoch checked successfully.",
+	})
+
+	checkDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "check_duration_seconds",
+		Help:      "Time spent checking the duties of an epoch.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300},
+	})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests to external services.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"component"})
+
+	requestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "request_errors_total",
+		Help:      "Requests to external services that failed or returned an error status.",
+	}, []string{"component"})
+)
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func SetValidatorLive(index domain.ValidatorIndex, live bool) {
+	validatorLive.WithLabelValues(label(index)).Set(boolToFloat(live))
+}
+
+func SetValidatorSlashed(index domain.ValidatorIndex, slashed bool) {
+	validatorSlashed.WithLabelValues(label(index)).Set(boolToFloat(slashed))
+}
+
+func IncValidatorProposal(index domain.ValidatorIndex, outcome domain.ProposalOutcome) {
+	validatorProposals.WithLabelValues(label(index), string(outcome)).Inc()
+}
+
+// SetValidatorTags replaces the info series of the tracked validators, dropping the ones no longer tracked
+func SetValidatorTags(indices []domain.ValidatorIndex, tags map[domain.ValidatorIndex]string) {
+	validatorInfo.Reset()
+	for _, index := range indices {
+		validatorInfo.WithLabelValues(label(index), tags[index]).Set(1)
+	}
+}
+
+// SetValidatorStatuses replaces the status series, leaving out the validators whose deposit was not processed yet
+func SetValidatorStatuses(lifecycles map[string]domain.ValidatorLifecycle) {
+	validatorStatus.Reset()
+	for _, lifecycle := range lifecycles {
+		if lifecycle.Validator != nil {
+			validatorStatus.WithLabelValues(label(lifecycle.Validator.Index), string(lifecycle.Status)).Set(1)
+		}
+	}
+}
+
+func SetLastProcessedEpoch(epoch domain.Epoch) {
+	lastProcessedEpoch.Set(float64(epoch))
+}
+
+func ObserveCheckDuration(d time.Duration) {
+	checkDuration.Observe(d.Seconds())
+}
+
+// NewTransport wraps an http.RoundTripper to record the latency and errors of the requests of a component.
+// If next is nil, http.DefaultTransport is used.
+func NewTransport(component string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &instrumentedTransport{component: component, next: next}
+}
+
+type instrumentedTransport struct {
+	component string
+	next      http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	requestDuration.WithLabelValues(t.component).Observe(time.Since(start).Seconds())
+	// 404 is not counted, beacon nodes answer it for empty slots
+	if err != nil || (resp.StatusCode >= 400 && resp.StatusCode != http.StatusNotFound) {
+		requestErrors.WithLabelValues(t.component).Inc()
+	}
+	return resp, err
+}
+
+func label(index domain.ValidatorIndex) string {
+	return strconv.FormatUint(uint64(index), 10)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}